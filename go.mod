@@ -6,9 +6,11 @@ require (
 	github.com/bflad/tfproviderlint v0.14.0
 	github.com/client9/misspell v0.3.4
 	github.com/golangci/golangci-lint v1.27.0
+	github.com/hashicorp/go-retryablehttp v0.6.6
 	github.com/hashicorp/terraform-plugin-sdk v1.10.0
 	github.com/newrelic/go-agent/v3 v3.4.0
 	github.com/newrelic/go-insights v1.0.3
 	github.com/newrelic/newrelic-client-go v0.27.1
 	github.com/stretchr/testify v1.6.0
+	golang.org/x/net v0.0.0-20200301022130-244492dfa37a
 )
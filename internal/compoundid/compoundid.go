@@ -0,0 +1,356 @@
+// Package compoundid parses and builds the IDs of resources that are made up of
+// several parts, such as a policy ID and a condition ID joined by a colon.
+//
+// Each kind of ID is described by a Schema, which lists every Format its IDs
+// may be given in. IDs are always written in the current format, but can be
+// parsed from any of them, so that IDs stored by older releases and IDs given
+// to `terraform import` keep working as formats change. IDs parsed from the
+// format of an older version are upgraded to the current one as they are
+// parsed.
+package compoundid
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Separator joins the parts of a compound ID.
+const Separator = ":"
+
+// Kind is the type of value held by a part of a compound ID.
+type Kind int
+
+const (
+	// Int parts hold integer IDs, which may use the full 64-bit range.
+	Int Kind = iota
+
+	// String parts hold any non-empty value without a separator.
+	String
+)
+
+// Part describes one part of a compound ID.
+type Part struct {
+	Name string
+	Kind Kind
+
+	// Repeated marks the last part of a format as taking one or more of the
+	// remaining items of an ID.
+	Repeated bool
+
+	// Attribute marks a part that is not kept in the resource ID, but is set
+	// on the resource attribute of the same name when the ID is imported.
+	Attribute bool
+}
+
+// Format is one layout of a compound ID. The version is that of the layout of
+// the parts kept in the resource ID, so formats which only add attribute parts
+// share the version of the format they extend.
+type Format struct {
+	Version int
+	Parts   []Part
+
+	// Upgrade sets the parts of the current format that are missing from an
+	// ID parsed in this format, for formats of an older version. Parts with
+	// the same name are carried over without it.
+	Upgrade func(id *ID) error
+}
+
+// String returns the layout of the format, e.g. <policy_id>:<condition_id>.
+func (f Format) String() string {
+	names := make([]string, len(f.Parts))
+
+	for i, p := range f.Parts {
+		names[i] = "<" + p.Name + ">"
+		if p.Repeated {
+			names[i] += "[" + Separator + "<" + p.Name + ">...]"
+		}
+	}
+
+	return strings.Join(names, Separator)
+}
+
+// matches reports whether an ID with the given number of items can be in the
+// format.
+func (f Format) matches(count int) bool {
+	if n := len(f.Parts); n > 0 && f.Parts[n-1].Repeated {
+		return count >= n
+	}
+
+	return count == len(f.Parts)
+}
+
+// Schema lists every format of one kind of compound ID. The current format is
+// the first of the newest version.
+type Schema struct {
+	Formats []Format
+}
+
+// Current returns the format new IDs are written in.
+func (s *Schema) Current() Format {
+	return s.byVersion()[0]
+}
+
+// New returns an ID in the current format with the given values of its parts,
+// which are either ints or strings.
+func (s *Schema) New(values ...interface{}) string {
+	items := make([]string, len(values))
+
+	for i, v := range values {
+		switch v := v.(type) {
+		case int:
+			items[i] = strconv.Itoa(v)
+		case []int:
+			items[i] = joinInts(v)
+		default:
+			items[i] = fmt.Sprint(v)
+		}
+	}
+
+	return strings.Join(items, Separator)
+}
+
+// Parse splits an ID into its parts, using the newest format with a matching
+// number of parts, and upgrades IDs parsed in the format of an older version
+// to the current one. The error returned for an ID that does not match any
+// format, or that has a part of the wrong kind, describes the expected
+// formats, so it can be shown to users as is.
+func (s *Schema) Parse(raw string) (*ID, error) {
+	items := strings.Split(raw, Separator)
+
+	for _, f := range s.byVersion() {
+		if !f.matches(len(items)) {
+			continue
+		}
+
+		id := &ID{
+			schema: s,
+			Format: f,
+			values: map[string][]string{},
+		}
+
+		for i, item := range items {
+			p := f.Parts[minInt(i, len(f.Parts)-1)]
+
+			if err := validate(p, item); err != nil {
+				return nil, fmt.Errorf("invalid ID %q: %s", raw, err)
+			}
+
+			id.values[p.Name] = append(id.values[p.Name], item)
+		}
+
+		if err := s.upgrade(id); err != nil {
+			return nil, fmt.Errorf("invalid ID %q: %s", raw, err)
+		}
+
+		return id, nil
+	}
+
+	return nil, fmt.Errorf("invalid ID %q: expected %s", raw, s.describe())
+}
+
+// byVersion returns the formats of the schema from the newest version to the
+// oldest, keeping the order of formats of the same version.
+func (s *Schema) byVersion() []Format {
+	formats := append([]Format{}, s.Formats...)
+
+	sort.SliceStable(formats, func(i, j int) bool {
+		return formats[i].Version > formats[j].Version
+	})
+
+	return formats
+}
+
+// upgrade sets the parts of the current format on an ID parsed in the format
+// of an older version.
+func (s *Schema) upgrade(id *ID) error {
+	current := s.Current()
+	if id.Format.Version >= current.Version {
+		return nil
+	}
+
+	if id.Format.Upgrade != nil {
+		if err := id.Format.Upgrade(id); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range current.Parts {
+		if p.Attribute || id.Has(p.Name) {
+			continue
+		}
+
+		return fmt.Errorf("%s is missing and cannot be upgraded from %s to %s", p.Name, id.Format, current)
+	}
+
+	return nil
+}
+
+// describe lists the formats of the schema for error messages.
+func (s *Schema) describe() string {
+	layouts := make([]string, len(s.Formats))
+
+	for i, f := range s.Formats {
+		layouts[i] = f.String()
+	}
+
+	if len(layouts) == 1 {
+		return layouts[0]
+	}
+
+	return strings.Join(layouts[:len(layouts)-1], ", ") + " or " + layouts[len(layouts)-1]
+}
+
+func validate(p Part, item string) error {
+	if item == "" {
+		return fmt.Errorf("%s must not be empty", p.Name)
+	}
+
+	if p.Kind != Int {
+		return nil
+	}
+
+	if _, err := parseInt(item); err != nil {
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			return fmt.Errorf("%s is out of range, got %q", p.Name, item)
+		}
+
+		return fmt.Errorf("%s must be an integer, got %q", p.Name, item)
+	}
+
+	return nil
+}
+
+// parseInt parses an integer ID, which must fit in an int on the platform the
+// provider is built for.
+func parseInt(item string) (int, error) {
+	n, err := strconv.ParseInt(item, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if int64(int(n)) != n {
+		return 0, &strconv.NumError{Func: "ParseInt", Num: item, Err: strconv.ErrRange}
+	}
+
+	return int(n), nil
+}
+
+func joinInts(ints []int) string {
+	items := make([]string, len(ints))
+
+	for i, n := range ints {
+		items[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(items, Separator)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// ID is a compound ID split into its parts.
+type ID struct {
+	// Format is the format the ID was parsed from.
+	Format Format
+
+	schema *Schema
+	values map[string][]string
+}
+
+// Has reports whether the ID has a part with the given name, which is not
+// the case for parts missing from the format the ID was parsed from.
+func (id *ID) Has(name string) bool {
+	_, ok := id.values[name]
+	return ok
+}
+
+// Int returns the value of an integer part, or 0 when the ID has no such part.
+func (id *ID) Int(name string) int {
+	ints := id.Ints(name)
+	if len(ints) == 0 {
+		return 0
+	}
+
+	return ints[0]
+}
+
+// Ints returns every value of a repeated integer part.
+func (id *ID) Ints(name string) []int {
+	ints := []int{}
+
+	for _, item := range id.values[name] {
+		// Parts are validated by Parse.
+		n, _ := parseInt(item)
+		ints = append(ints, n)
+	}
+
+	return ints
+}
+
+// Value returns the value of a part as given in the ID, or an empty string
+// when the ID has no such part.
+func (id *ID) Value(name string) string {
+	if items := id.values[name]; len(items) > 0 {
+		return items[0]
+	}
+
+	return ""
+}
+
+// Set sets the value of a part, which is either an int, a []int for a repeated
+// part, or a string. It is meant for upgrading IDs from older formats.
+func (id *ID) Set(name string, value interface{}) {
+	switch v := value.(type) {
+	case int:
+		id.values[name] = []string{strconv.Itoa(v)}
+	case []int:
+		items := make([]string, len(v))
+		for i, n := range v {
+			items[i] = strconv.Itoa(n)
+		}
+		id.values[name] = items
+	default:
+		id.values[name] = []string{fmt.Sprint(v)}
+	}
+}
+
+// Attributes returns the values of the attribute parts of the ID, keyed by
+// their names.
+func (id *ID) Attributes() map[string]interface{} {
+	attrs := map[string]interface{}{}
+
+	for _, p := range id.Format.Parts {
+		if !p.Attribute || !id.Has(p.Name) {
+			continue
+		}
+
+		if p.Kind == Int {
+			attrs[p.Name] = id.Int(p.Name)
+		} else {
+			attrs[p.Name] = id.Value(p.Name)
+		}
+	}
+
+	return attrs
+}
+
+// String returns the ID in the current format of its schema, without any
+// attribute parts.
+func (id *ID) String() string {
+	items := []string{}
+
+	for _, p := range id.schema.Current().Parts {
+		if !p.Attribute {
+			items = append(items, id.values[p.Name]...)
+		}
+	}
+
+	return strings.Join(items, Separator)
+}
@@ -0,0 +1,178 @@
+package compoundid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testConditionID = &Schema{
+	Formats: []Format{
+		{
+			Version: 1,
+			Parts: []Part{
+				{Name: "policy_id", Kind: Int},
+				{Name: "condition_id", Kind: Int},
+			},
+		},
+		{
+			Version: 1,
+			Parts: []Part{
+				{Name: "policy_id", Kind: Int},
+				{Name: "condition_id", Kind: Int},
+				{Name: "type", Kind: String, Attribute: true},
+			},
+		},
+	},
+}
+
+var testChannelsID = &Schema{
+	Formats: []Format{
+		{
+			Version: 1,
+			Parts: []Part{
+				{Name: "policy_id", Kind: Int},
+				{Name: "channel_id", Kind: Int, Repeated: true},
+			},
+		},
+	},
+}
+
+func TestParse(t *testing.T) {
+	id, err := testConditionID.Parse("123:456")
+	require.NoError(t, err)
+	require.Equal(t, 123, id.Int("policy_id"))
+	require.Equal(t, 456, id.Int("condition_id"))
+	require.False(t, id.Has("type"))
+	require.Equal(t, "", id.Value("type"))
+	require.Equal(t, "123:456", id.String())
+}
+
+func TestParse_64BitIDs(t *testing.T) {
+	id, err := testConditionID.Parse("4294967296:9007199254740993")
+	require.NoError(t, err)
+	require.Equal(t, 4294967296, id.Int("policy_id"))
+	require.Equal(t, 9007199254740993, id.Int("condition_id"))
+}
+
+func TestParse_Attributes(t *testing.T) {
+	id, err := testConditionID.Parse("123:456:static")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"type": "static"}, id.Attributes())
+	require.Equal(t, "123:456", id.String())
+}
+
+func TestParse_Repeated(t *testing.T) {
+	id, err := testChannelsID.Parse("1:2:3")
+	require.NoError(t, err)
+	require.Equal(t, 1, id.Int("policy_id"))
+	require.Equal(t, []int{2, 3}, id.Ints("channel_id"))
+	require.Equal(t, "1:2:3", id.String())
+
+	_, err = testChannelsID.Parse("1")
+	require.EqualError(t, err, `invalid ID "1": expected <policy_id>:<channel_id>[:<channel_id>...]`)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"":                         `invalid ID "": expected <policy_id>:<condition_id> or <policy_id>:<condition_id>:<type>`,
+		"123":                      `invalid ID "123": expected <policy_id>:<condition_id> or <policy_id>:<condition_id>:<type>`,
+		"1:2:3:4":                  `invalid ID "1:2:3:4": expected <policy_id>:<condition_id> or <policy_id>:<condition_id>:<type>`,
+		"abc:456":                  `invalid ID "abc:456": policy_id must be an integer, got "abc"`,
+		"123:":                     `invalid ID "123:": condition_id must not be empty`,
+		"123:456:":                 `invalid ID "123:456:": type must not be empty`,
+		"123:92233720368547758070": `invalid ID "123:92233720368547758070": condition_id is out of range, got "92233720368547758070"`,
+	}
+
+	for raw, expected := range cases {
+		_, err := testConditionID.Parse(raw)
+		require.EqualError(t, err, expected, raw)
+	}
+}
+
+// Condition IDs at version 1 had no type, as every condition was static.
+var testTypedConditionID = &Schema{
+	Formats: []Format{
+		{
+			Version: 2,
+			Parts: []Part{
+				{Name: "policy_id", Kind: Int},
+				{Name: "condition_id", Kind: Int},
+				{Name: "type", Kind: String},
+			},
+		},
+		{
+			Version: 1,
+			Parts: []Part{
+				{Name: "policy_id", Kind: Int},
+				{Name: "condition_id", Kind: Int},
+			},
+			Upgrade: func(id *ID) error {
+				id.Set("type", "static")
+				return nil
+			},
+		},
+	},
+}
+
+func TestParse_Upgrade(t *testing.T) {
+	id, err := testTypedConditionID.Parse("123:456")
+	require.NoError(t, err)
+	require.Equal(t, 1, id.Format.Version)
+	require.Equal(t, "static", id.Value("type"))
+	require.Equal(t, "123:456:static", id.String())
+
+	id, err = testTypedConditionID.Parse("123:456:baseline")
+	require.NoError(t, err)
+	require.Equal(t, 2, id.Format.Version)
+	require.Equal(t, "123:456:baseline", id.String())
+}
+
+func TestParse_UpgradeMissingPart(t *testing.T) {
+	s := &Schema{
+		Formats: []Format{
+			testTypedConditionID.Formats[0],
+			{
+				Version: 1,
+				Parts:   testTypedConditionID.Formats[1].Parts,
+			},
+		},
+	}
+
+	_, err := s.Parse("123:456")
+	require.EqualError(t, err, `invalid ID "123:456": type is missing and cannot be upgraded from <policy_id>:<condition_id> to <policy_id>:<condition_id>:<type>`)
+}
+
+func TestParse_NewestVersionFirst(t *testing.T) {
+	// Formats of the same shape are told apart by their version, so the
+	// newest is used whatever the order they are listed in.
+	s := &Schema{
+		Formats: []Format{
+			{
+				Version: 2,
+				Parts: []Part{
+					{Name: "policy_id", Kind: Int},
+					{Name: "channel_id", Kind: Int, Repeated: true},
+				},
+			},
+			{
+				Version: 1,
+				Parts: []Part{
+					{Name: "policy_id", Kind: Int},
+					{Name: "channel_id", Kind: Int},
+				},
+			},
+		},
+	}
+	s.Formats[0], s.Formats[1] = s.Formats[1], s.Formats[0]
+
+	id, err := s.Parse("1:2")
+	require.NoError(t, err)
+	require.Equal(t, 2, id.Format.Version)
+}
+
+func TestNew(t *testing.T) {
+	require.Equal(t, "123:456", testConditionID.New(123, 456))
+	require.Equal(t, "1:2:3", testChannelsID.New(1, []int{2, 3}))
+	require.Equal(t, "4294967296:abc", testConditionID.New(4294967296, "abc"))
+}
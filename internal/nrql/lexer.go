@@ -0,0 +1,286 @@
+package nrql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind is the kind of a token of a NRQL query.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+
+	// Words are bare identifiers and keywords, which are told apart by the
+	// parser from where they appear.
+	tokenWord
+
+	// Quoted identifiers are enclosed in backticks.
+	tokenQuotedIdent
+
+	// Strings are enclosed in single or double quotes, or are regular
+	// expressions written as r'...'.
+	tokenString
+	tokenNumber
+
+	// Invalid numbers are numbers followed by letters, as in `1h`. They are
+	// left for the parser to report, as what was meant depends on where
+	// they appear.
+	tokenInvalidNumber
+
+	// Operators and punctuation.
+	tokenSymbol
+)
+
+// token is one token of a NRQL query, and the position of its first byte.
+type token struct {
+	kind  tokenKind
+	text  string
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenQuotedIdent:
+		return "`" + t.value + "`"
+	case tokenString:
+		return "'" + t.value + "'"
+	}
+
+	return fmt.Sprintf("%q", t.text)
+}
+
+// is reports whether a token is the given keyword or symbol. Keywords are
+// matched regardless of their case.
+func (t token) is(text string) bool {
+	switch t.kind {
+	case tokenWord:
+		return strings.EqualFold(t.text, text)
+	case tokenSymbol:
+		return t.text == text
+	}
+
+	return false
+}
+
+// symbols are the operators and punctuation of NRQL, longest first so that
+// `<=` is not read as `<`.
+var symbols = []string{"!=", "<>", "<=", ">=", "=", "<", ">", "+", "-", "*", "/", "%", "(", ")", ",", ":"}
+
+// lex splits a NRQL query into tokens, which always end with a tokenEOF.
+func lex(query string) ([]token, error) {
+	tokens := []token{}
+	pos := 0
+
+	for pos < len(query) {
+		c := query[pos]
+
+		switch {
+		case unicode.IsSpace(rune(c)):
+			pos++
+
+		case strings.HasPrefix(query[pos:], "//"), strings.HasPrefix(query[pos:], "--"):
+			end := strings.IndexByte(query[pos:], '\n')
+			if end < 0 {
+				end = len(query) - pos
+			}
+			pos += end
+
+		case strings.HasPrefix(query[pos:], "/*"):
+			end := strings.Index(query[pos+2:], "*/")
+			if end < 0 {
+				return nil, &SyntaxError{Pos: pos, Msg: "unterminated comment"}
+			}
+			pos += end + 4
+
+		case c == '\'' || c == '"':
+			value, end, err := lexQuoted(query, pos, c)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: query[pos:end], value: value, pos: pos})
+			pos = end
+
+		case (c == 'r' || c == 'R') && pos+1 < len(query) && (query[pos+1] == '\'' || query[pos+1] == '"'):
+			value, end, err := lexQuoted(query, pos+1, query[pos+1])
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: query[pos:end], value: value, pos: pos})
+			pos = end
+
+		case c == '`':
+			end := strings.IndexByte(query[pos+1:], '`')
+			if end < 0 {
+				return nil, &SyntaxError{Pos: pos, Msg: "unterminated quoted identifier"}
+			}
+			end += pos + 1
+
+			if end == pos+1 {
+				return nil, &SyntaxError{Pos: pos, Msg: "empty quoted identifier"}
+			}
+
+			tokens = append(tokens, token{kind: tokenQuotedIdent, text: query[pos : end+1], value: query[pos+1 : end], pos: pos})
+			pos = end + 1
+
+		case isDigit(c) || (c == '.' && pos+1 < len(query) && isDigit(query[pos+1])):
+			end := lexNumber(query, pos)
+			kind := tokenNumber
+			if end < len(query) && isWordByte(query[end]) {
+				kind = tokenInvalidNumber
+				end = wordEnd(query, end)
+			}
+
+			tokens = append(tokens, token{kind: kind, text: query[pos:end], value: query[pos:end], pos: pos})
+			pos = end
+
+		case isWordStart(c):
+			value, end, err := lexWord(query, pos)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, token{kind: tokenWord, text: query[pos:end], value: value, pos: pos})
+			pos = end
+
+		default:
+			symbol := ""
+			for _, s := range symbols {
+				if strings.HasPrefix(query[pos:], s) {
+					symbol = s
+					break
+				}
+			}
+
+			if symbol == "" {
+				return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", query[pos:pos+1])}
+			}
+
+			tokens = append(tokens, token{kind: tokenSymbol, text: symbol, value: symbol, pos: pos})
+			pos += len(symbol)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(query)}), nil
+}
+
+// lexQuoted reads the string starting with the quote at pos. A quote is
+// escaped by a backslash or by doubling it.
+func lexQuoted(query string, pos int, quote byte) (string, int, error) {
+	var value strings.Builder
+
+	for i := pos + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if i+1 < len(query) {
+				i++
+				if query[i] != quote {
+					value.WriteByte('\\')
+				}
+				value.WriteByte(query[i])
+			}
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				value.WriteByte(quote)
+				i++
+				continue
+			}
+
+			return value.String(), i + 1, nil
+		default:
+			value.WriteByte(query[i])
+		}
+	}
+
+	return "", 0, &SyntaxError{Pos: pos, Msg: "unterminated string"}
+}
+
+// lexNumber returns the end of the number starting at pos, which may have a
+// fraction and an exponent.
+func lexNumber(query string, pos int) int {
+	end := pos
+	for end < len(query) && isDigit(query[end]) {
+		end++
+	}
+
+	if end < len(query) && query[end] == '.' {
+		end++
+		for end < len(query) && isDigit(query[end]) {
+			end++
+		}
+	}
+
+	if end < len(query) && (query[end] == 'e' || query[end] == 'E') {
+		exp := end + 1
+		if exp < len(query) && (query[exp] == '+' || query[exp] == '-') {
+			exp++
+		}
+
+		if exp < len(query) && isDigit(query[exp]) {
+			end = exp
+			for end < len(query) && isDigit(query[end]) {
+				end++
+			}
+		}
+	}
+
+	return end
+}
+
+// lexWord reads the word starting at pos. Words may be dotted, and the parts
+// after a dot may be quoted with backticks, as in tags.`k8s.cluster`, which
+// are left out of its value.
+func lexWord(query string, pos int) (string, int, error) {
+	var value strings.Builder
+
+	end := pos
+	for {
+		start := end
+		end = wordEnd(query, end)
+		value.WriteString(query[start:end])
+
+		if end == start || query[end-1] != '.' || end >= len(query) || query[end] != '`' {
+			return value.String(), end, nil
+		}
+
+		quote := strings.IndexByte(query[end+1:], '`')
+		if quote < 0 {
+			return "", 0, &SyntaxError{Pos: end, Msg: "unterminated quoted identifier"}
+		}
+		if quote == 0 {
+			return "", 0, &SyntaxError{Pos: end, Msg: "empty quoted identifier"}
+		}
+
+		value.WriteString(query[end+1 : end+1+quote])
+		end += quote + 2
+	}
+}
+
+// wordEnd returns the end of the word starting at pos. Words may be dotted,
+// as in `request.uri`.
+func wordEnd(query string, pos int) int {
+	end := pos
+	for end < len(query) && (isWordByte(query[end]) || query[end] == '.') {
+		end++
+	}
+
+	return end
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isWordByte(c byte) bool {
+	return isWordStart(c) || isDigit(c)
+}
@@ -0,0 +1,281 @@
+// Package nrql checks the syntax of NRQL queries without a New Relic account,
+// so that mistakes in queries can be reported when planning rather than when
+// the API rejects them, or never for queries that are only stored.
+//
+// The parser covers the structure of queries: the SELECT, FROM, WHERE, FACET,
+// SINCE, UNTIL, COMPARE WITH, TIMESERIES and LIMIT clauses, along with the
+// expressions they hold. It does not know the functions or attributes that
+// exist, which are left for New Relic to check. As NRQL may gain keywords the
+// parser does not know yet, words it does not know are reported apart from
+// the mistakes in the structure of queries, and the rest of the query is
+// still parsed.
+package nrql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntaxError is an error in the syntax of a query, at the given byte offset.
+type SyntaxError struct {
+	Pos int
+	Msg string
+
+	// Unknown is set for words the parser does not know where a clause or
+	// an operator is expected, which may be mistakes or keywords added to
+	// NRQL since. Other errors are mistakes in the structure of the query.
+	Unknown bool
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos+1, e.Msg)
+}
+
+// Query is a parsed NRQL query. Clauses that are not in the query are nil.
+type Query struct {
+	Select []*SelectItem
+	From   []*FromItem
+	Where  Expr
+	Facet  []*SelectItem
+
+	Since       *Time
+	Until       *Time
+	CompareWith *Time
+	Timeseries  *Timeseries
+
+	Limit   *Literal
+	Offset  *Literal
+	OrderBy []*OrderItem
+
+	// Timezone is the value of a WITH TIMEZONE clause.
+	Timezone *Literal
+
+	Extrapolate bool
+	Raw         bool
+
+	// ShowEventTypes is set for SHOW EVENT TYPES queries, which have no
+	// SELECT or FROM clause.
+	ShowEventTypes bool
+}
+
+// SelectItem is a value selected or faceted by a query, with its alias.
+type SelectItem struct {
+	Expr  Expr
+	Alias string
+}
+
+// FromItem is an event type a query reads from, or a nested query.
+type FromItem struct {
+	EventType string
+	Query     *Query
+}
+
+// OrderItem is an expression a query is ordered by.
+type OrderItem struct {
+	Expr       Expr
+	Descending bool
+}
+
+// Time is a point in time, given as a string, a number of milliseconds since
+// the epoch, a duration ago, or a keyword such as TODAY or LAST WEEK.
+type Time struct {
+	Text string
+}
+
+// Timeseries is a TIMESERIES clause, with its bucket and its SLIDE BY window.
+type Timeseries struct {
+	Bucket  string
+	SlideBy string
+}
+
+// Expr is an expression of a query.
+type Expr interface {
+	expr()
+}
+
+// Ident is an attribute, or the `*` that stands for every attribute.
+type Ident struct {
+	Name string
+}
+
+// Literal is a string, number, boolean or duration value, or NULL.
+type Literal struct {
+	Kind  LiteralKind
+	Value string
+}
+
+// LiteralKind is the type of a Literal.
+type LiteralKind int
+
+// The types of literals.
+const (
+	String LiteralKind = iota
+	Number
+	Boolean
+	Null
+	Duration
+)
+
+// Call is a call of a function.
+type Call struct {
+	Name string
+	Args []Expr
+}
+
+// Condition is a WHERE clause given to a function, as in
+// `filter(count(*), WHERE error IS TRUE)`.
+type Condition struct {
+	Where Expr
+	Alias string
+}
+
+// NamedArg is an argument of a function given by name, as in
+// `apdex(duration, t: 0.5)`.
+type NamedArg struct {
+	Name  string
+	Value Expr
+}
+
+// Unary is an operation on one value, such as NOT or a negation.
+type Unary struct {
+	Op string
+	X  Expr
+}
+
+// Binary is an operation on two values, such as a comparison or AND.
+type Binary struct {
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+// List is a list of values, as found on the right of IN.
+type List struct {
+	Items []Expr
+}
+
+// Subquery is a query nested in an expression, as found on the right of IN.
+type Subquery struct {
+	Query *Query
+}
+
+func (*Ident) expr()     {}
+func (*Literal) expr()   {}
+func (*Call) expr()      {}
+func (*Condition) expr() {}
+func (*NamedArg) expr()  {}
+func (*Unary) expr()     {}
+func (*Binary) expr()    {}
+func (*List) expr()      {}
+func (*Subquery) expr()  {}
+
+// Parse parses a NRQL query. When the only errors of the query are unknown
+// words, it is returned along with the first of them, parsed without the
+// clauses they start.
+func Parse(query string) (*Query, error) {
+	p, err := newParser(query)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectEOF(); err != nil {
+		return nil, err
+	}
+
+	if len(p.unknown) > 0 {
+		return q, unknownClause(p.unknown[0])
+	}
+
+	return q, nil
+}
+
+// ParseCondition parses a condition on its own, written as it would be in a
+// WHERE clause. This is also the syntax of entity search queries. When it is
+// followed by an unknown word, it is returned along with an error for it.
+func ParseCondition(condition string) (Expr, error) {
+	p, err := newParser(condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind == tokenEOF {
+		return nil, &SyntaxError{Pos: 0, Msg: "expected a condition, got end of query"}
+	}
+
+	e, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind == tokenWord && !isReserved(t) {
+		return e, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unknown keyword %s", t), Unknown: true}
+	}
+
+	if err := p.expectEOF(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// aggregationFunctions are the functions of NRQL that aggregate the events of
+// a query into a value.
+var aggregationFunctions = map[string]bool{
+	"aggregationendtime": true,
+	"apdex":              true,
+	"average":            true,
+	"bucketpercentile":   true,
+	"cardinality":        true,
+	"cdfpercentage":      true,
+	"count":              true,
+	"derivative":         true,
+	"earliest":           true,
+	"filter":             true,
+	"funnel":             true,
+	"histogram":          true,
+	"keyset":             true,
+	"latest":             true,
+	"latestrate":         true,
+	"max":                true,
+	"median":             true,
+	"min":                true,
+	"percentage":         true,
+	"percentile":         true,
+	"predictlinear":      true,
+	"rate":               true,
+	"stddev":             true,
+	"stdvar":             true,
+	"sum":                true,
+	"uniquecount":        true,
+	"uniques":            true,
+}
+
+// IsAggregation reports whether an expression calls an aggregation function,
+// such as count() or average(), anywhere within it.
+func IsAggregation(e Expr) bool {
+	switch e := e.(type) {
+	case *Call:
+		if aggregationFunctions[strings.ToLower(e.Name)] {
+			return true
+		}
+
+		for _, a := range e.Args {
+			if IsAggregation(a) {
+				return true
+			}
+		}
+	case *NamedArg:
+		return IsAggregation(e.Value)
+	case *Unary:
+		return IsAggregation(e.X)
+	case *Binary:
+		return IsAggregation(e.Left) || IsAggregation(e.Right)
+	}
+
+	return false
+}
@@ -0,0 +1,164 @@
+package nrql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	queries := []string{
+		"SELECT count(*) FROM Transaction",
+		"select COUNT(*) from Transaction since 1 day ago",
+		"FROM Transaction SELECT average(duration) WHERE appName = 'checkout' FACET name LIMIT 10",
+		"SELECT * FROM Log WHERE message LIKE '%error%' AND level NOT IN ('debug', 'info') LIMIT MAX",
+		"SELECT percentile(duration, 95, 99) AS 'Latency' FROM Transaction TIMESERIES 5 minutes SINCE 1 week ago UNTIL 1 day ago",
+		"SELECT count(*) FROM Transaction TIMESERIES AUTO COMPARE WITH 1 week ago",
+		"SELECT count(*) FROM Transaction TIMESERIES 1 minute SLIDE BY 30 seconds SINCE '2020-06-01 00:00:00' UNTIL 1591833600000",
+		"SELECT filter(count(*), WHERE httpResponseCode >= '500') / count(*) * 100 AS `Error rate` FROM Transaction",
+		"SELECT percentage(count(*), WHERE error IS TRUE) FROM Transaction WHERE `request.uri` IS NOT NULL",
+		"SELECT funnel(session, WHERE pageUrl LIKE '%/cart' AS 'Cart', WHERE pageUrl LIKE '%/checkout' AS 'Checkout') FROM PageView SINCE this week",
+		"SELECT uniqueCount(session) FROM PageView FACET cases(WHERE duration < 1 AS 'Fast', WHERE duration >= 1 AS 'Slow')",
+		"SELECT rate(count(*), 1 minute) FROM Transaction WHERE NOT (appName = 'a' OR appName = 'b') SINCE yesterday",
+		"SELECT capture(name, r'Controller/(?P<controller>\\w+)/.*') FROM Transaction SINCE last month",
+		"SELECT count(*) FROM Transaction, PageView WHERE duration > -1.5e3 FACET weekdayOf(timestamp) ORDER BY count(*) DESC",
+		"SELECT average(cpuPercent) FROM SystemSample WHERE hostname IN (FROM Transaction SELECT uniques(host))",
+		"SELECT max(total) FROM (SELECT count(*) AS total FROM Transaction TIMESERIES 1 hour) SINCE today",
+		"SELECT count(*) FROM Transaction WITH TIMEZONE 'Europe/London' EXTRAPOLATE",
+		"SELECT latest(timestamp) FROM Metric WHERE metricName = \"apm.service.transaction.duration\" FACET dimensions()",
+		"SHOW EVENT TYPES SINCE 1 week ago",
+		"SELECT apdex(duration, t: 0.5) FROM Transaction",
+		"SELECT count(*) FROM Transaction // all of them\nSINCE 1 day ago",
+		"SELECT count(*) -- all of them\nFROM Transaction",
+		"SELECT count(*) /* all\nof them */ FROM Transaction",
+		"SELECT average(value) FROM Metric TIMESERIES 1 minute RAW",
+	}
+
+	for _, query := range queries {
+		_, err := Parse(query)
+		require.NoError(t, err, query)
+	}
+}
+
+func TestParse_Clauses(t *testing.T) {
+	q, err := Parse("SELECT sum(duration) AS total, count(*) FROM Transaction WHERE a = 1 FACET b TIMESERIES 5 minutes SINCE 1 day ago UNTIL now COMPARE WITH 1 week ago")
+	require.NoError(t, err)
+
+	require.Len(t, q.Select, 2)
+	require.Equal(t, "total", q.Select[0].Alias)
+	require.True(t, IsAggregation(q.Select[0].Expr))
+	require.Equal(t, []*FromItem{{EventType: "Transaction"}}, q.From)
+	require.Equal(t, &Binary{Op: "=", Left: &Ident{Name: "a"}, Right: &Literal{Kind: Number, Value: "1"}}, q.Where)
+	require.Equal(t, []*SelectItem{{Expr: &Ident{Name: "b"}}}, q.Facet)
+	require.Equal(t, &Timeseries{Bucket: "5 MINUTES"}, q.Timeseries)
+	require.Equal(t, &Time{Text: "1 day AGO"}, q.Since)
+	require.Equal(t, &Time{Text: "NOW"}, q.Until)
+	require.Equal(t, &Time{Text: "1 week AGO"}, q.CompareWith)
+}
+
+func TestParse_NamedArgs(t *testing.T) {
+	q, err := Parse("SELECT apdex(duration, t: 0.5) FROM Transaction RAW")
+	require.NoError(t, err)
+
+	require.Equal(t, &Call{Name: "apdex", Args: []Expr{
+		&Ident{Name: "duration"},
+		&NamedArg{Name: "t", Value: &Literal{Kind: Number, Value: "0.5"}},
+	}}, q.Select[0].Expr)
+	require.True(t, q.Raw)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"":                                             "syntax error at position 1: expected a SELECT clause",
+		"SELECT count(*)":                              "syntax error at position 1: expected a FROM clause",
+		"SELECT count(*) FORM Transaction":             `syntax error at position 17: unknown keyword "FORM", did you mean FROM?`,
+		"SELEC count(*) FROM Transaction":              `syntax error at position 1: unknown keyword "SELEC", did you mean SELECT?`,
+		"SELECT count(* FROM Transaction":              `syntax error at position 16: expected , or ) in the arguments of count, got "FROM"`,
+		"SELECT count(*) FROM Transaction WHERE":       "syntax error at position 39: expected a value, got end of query",
+		"SELECT count(*) FROM Transaction WHERE a = ":  "syntax error at position 44: expected a value, got end of query",
+		"SELECT count(*) FROM Transaction SINCE 1 day": `syntax error at position 45: expected AGO, got end of query`,
+		"SELECT count(*) FROM Transaction SINCE 1 day ago SINCE 2 days ago": "syntax error at position 50: SINCE is given more than once",
+		"SELECT count(*) FROM Transaction WHERE name = 'foo":                "syntax error at position 47: unterminated string",
+		"SELECT count(*) FROM Transaction TIMESERIES 5":                     "syntax error at position 46: expected a unit of time after 5, got end of query",
+		"SELECT count(*) FROM Transaction COMPARE 1 week ago":               `syntax error at position 42: expected WITH, got "1"`,
+		"SELECT count(*) FROM Transaction LIMIT 1.5":                        `syntax error at position 40: expected a whole number, got "1.5"`,
+		"SELECT count(*) FROM Transaction WHERE a IS 1":                     `syntax error at position 45: expected NULL, TRUE or FALSE after IS, got "1"`,
+		"SELECT count(*) FROM Transaction WHERE a = 1)":                     `syntax error at position 45: unexpected ")"`,
+		"SELECT count(*) AS FROM Transaction":                               `syntax error at position 20: expected an alias after AS, got "FROM"`,
+		"SELECT count(*) FROM Transaction WHERE a = 1 ; DROP":               `syntax error at position 46: unexpected character ";"`,
+		"SELECT count(*) FROM Transaction /* all":                           "syntax error at position 34: unterminated comment",
+		"SELECT count(*) FROM Transaction WHERE tags.`a = 1":                "syntax error at position 45: unterminated quoted identifier",
+		"SELECT count(*) FROM Transaction WHERE a = 5abc":                   `syntax error at position 44: invalid number "5abc"`,
+		"SELECT count(*) FROM Transaction SINCE -1h":                        `syntax error at position 40: expected a time such as '1 hour ago', got "-": times in the past are written with AGO rather than as negative numbers`,
+		"SELECT count(*) FROM Transaction SINCE 1h ago":                     `syntax error at position 40: expected a time such as '1 hour ago', got "1h": units of time are written in full, after a space`,
+		"SELECT count(*) FROM Transaction TIMESERIES 5m":                    `syntax error at position 45: expected a duration such as '5 minutes', got "5m": units of time are written in full, after a space`,
+	}
+
+	for query, expected := range cases {
+		q, err := Parse(query)
+		require.EqualError(t, err, expected, query)
+		require.False(t, err.(*SyntaxError).Unknown, query)
+		require.Nil(t, q, query)
+	}
+}
+
+func TestParse_Unknown(t *testing.T) {
+	cases := map[string]string{
+		"SELECT count(*) FROM Transaction FACT name":                              `syntax error at position 34: unknown keyword "FACT", did you mean FACET?`,
+		"SELECT count(*) FROM Transaction PREDICT BY 1 hour":                      `syntax error at position 34: unknown keyword "PREDICT", expected a clause such as WHERE, FACET or SINCE`,
+		"SELECT count(*) FROM Transaction WHERE a > 1 BUCKET (a) SINCE 1 day ago": `syntax error at position 46: unknown keyword "BUCKET", expected a clause such as WHERE, FACET or SINCE`,
+	}
+
+	for query, expected := range cases {
+		q, err := Parse(query)
+		require.EqualError(t, err, expected, query)
+		require.True(t, err.(*SyntaxError).Unknown, query)
+
+		// The clauses around unknown words are still parsed.
+		require.NotNil(t, q, query)
+		require.Equal(t, []*FromItem{{EventType: "Transaction"}}, q.From, query)
+	}
+
+	q, _ := Parse("SELECT count(*) FROM Transaction WHERE a > 1 BUCKET (a) SINCE 1 day ago")
+	require.Equal(t, &Time{Text: "1 day AGO"}, q.Since)
+}
+
+func TestParseCondition(t *testing.T) {
+	_, err := ParseCondition("domain IN ('APM', 'BROWSER') AND name LIKE '%checkout%' AND tags.environment = 'production'")
+	require.NoError(t, err)
+
+	_, err = ParseCondition("tags.`k8s.cluster` = 'a' AND tags.`team`.name = 'b'")
+	require.NoError(t, err)
+
+	_, err = ParseCondition("")
+	require.EqualError(t, err, "syntax error at position 1: expected a condition, got end of query")
+
+	_, err = ParseCondition("name = 'foo' AND")
+	require.EqualError(t, err, "syntax error at position 17: expected a value, got end of query")
+
+	_, err = ParseCondition("name = 'foo' FACET bar")
+	require.EqualError(t, err, `syntax error at position 14: unexpected "FACET"`)
+	require.False(t, err.(*SyntaxError).Unknown)
+
+	_, err = ParseCondition("name = 'foo' MATCHES bar")
+	require.EqualError(t, err, `syntax error at position 14: unknown keyword "MATCHES"`)
+	require.True(t, err.(*SyntaxError).Unknown)
+}
+
+func TestIsAggregation(t *testing.T) {
+	cases := map[string]bool{
+		"count(*)":                  true,
+		"average(duration) * 1000":  true,
+		"-uniqueCount(session)":     true,
+		"round(latest(cpuPercent))": true,
+		"duration":                  false,
+		"round(duration)":           false,
+		"if(error, 1, 0)":           false,
+	}
+
+	for expr, expected := range cases {
+		q, err := Parse("SELECT " + expr + " FROM Transaction")
+		require.NoError(t, err, expr)
+		require.Equal(t, expected, IsAggregation(q.Select[0].Expr), expr)
+	}
+}
@@ -0,0 +1,930 @@
+package nrql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reserved are the keywords that cannot be used as bare attribute names, as
+// they start or end clauses and expressions. Attributes with these names must
+// be quoted with backticks.
+var reserved = map[string]bool{
+	"AND": true, "AS": true, "ASC": true, "BY": true, "COMPARE": true,
+	"DESC": true, "EXTRAPOLATE": true, "FACET": true, "FROM": true,
+	"IN": true, "IS": true, "LIKE": true, "LIMIT": true, "NOT": true,
+	"OFFSET": true, "OR": true, "ORDER": true, "RLIKE": true,
+	"SELECT": true, "SINCE": true, "SLIDE": true, "TIMESERIES": true,
+	"UNTIL": true, "WHERE": true, "WITH": true,
+}
+
+// clauses are the keywords that start the clauses of a query, and the names of
+// the clauses they start.
+var clauses = map[string]string{
+	"SELECT": "SELECT", "FROM": "FROM", "WHERE": "WHERE", "FACET": "FACET",
+	"SINCE": "SINCE", "UNTIL": "UNTIL", "COMPARE": "COMPARE WITH",
+	"TIMESERIES": "TIMESERIES", "LIMIT": "LIMIT", "OFFSET": "OFFSET",
+	"ORDER": "ORDER BY", "WITH": "WITH TIMEZONE", "EXTRAPOLATE": "EXTRAPOLATE",
+	"RAW": "RAW",
+}
+
+// timeUnits are the units of durations, in the singular.
+var timeUnits = map[string]bool{
+	"SECOND": true, "MINUTE": true, "HOUR": true, "DAY": true,
+	"WEEK": true, "MONTH": true, "QUARTER": true, "YEAR": true,
+}
+
+// comparisons are the operators that compare two values.
+var comparisons = map[string]bool{
+	"=": true, "!=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true,
+}
+
+// parser is a recursive descent parser of NRQL.
+type parser struct {
+	tokens []token
+	pos    int
+
+	// The unknown words found where a clause was expected.
+	unknown []token
+}
+
+func newParser(query string) (*parser, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parser{tokens: tokens}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+
+	return p.tokens[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+// accept consumes the next token if it is the given keyword or symbol.
+func (p *parser) accept(text string) bool {
+	if p.peek().is(text) {
+		p.next()
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.errorf("expected %s, got %s", text, p.peek())
+	}
+
+	return nil
+}
+
+func (p *parser) expectEOF() error {
+	if t := p.peek(); t.kind != tokenEOF {
+		return p.errorf("unexpected %s", t)
+	}
+
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Pos: p.peek().pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// isReserved reports whether a token is a keyword that cannot be an
+// attribute.
+func isReserved(t token) bool {
+	return t.kind == tokenWord && reserved[strings.ToUpper(t.text)]
+}
+
+// parseQuery parses the clauses of a query, which may come in any order as
+// long as there is a SELECT and a FROM clause. Clauses starting with unknown
+// words are skipped.
+func (p *parser) parseQuery() (*Query, error) {
+	q := &Query{}
+	start := p.peek()
+	seen := map[string]bool{}
+
+	if p.accept("SHOW") {
+		if err := p.expect("EVENT"); err != nil {
+			return nil, err
+		}
+
+		if err := p.expect("TYPES"); err != nil {
+			return nil, err
+		}
+
+		q.ShowEventTypes = true
+	}
+
+	for {
+		t := p.peek()
+		if t.kind != tokenWord {
+			break
+		}
+
+		clause, ok := clauses[strings.ToUpper(t.text)]
+		if !ok {
+			p.unknown = append(p.unknown, t)
+			p.skipClause()
+			continue
+		}
+
+		if seen[clause] {
+			return nil, p.errorf("%s is given more than once", clause)
+		}
+		seen[clause] = true
+
+		p.next()
+
+		var err error
+
+		switch clause {
+		case "SELECT":
+			q.Select, err = p.parseSelect()
+		case "FROM":
+			q.From, err = p.parseFrom()
+		case "WHERE":
+			q.Where, err = p.parseExpr()
+		case "FACET":
+			q.Facet, err = p.parseSelectItems()
+		case "SINCE":
+			q.Since, err = p.parseTime()
+		case "UNTIL":
+			q.Until, err = p.parseTime()
+		case "COMPARE WITH":
+			if err = p.expect("WITH"); err == nil {
+				q.CompareWith, err = p.parseTime()
+			}
+		case "TIMESERIES":
+			q.Timeseries, err = p.parseTimeseries()
+		case "LIMIT":
+			q.Limit, err = p.parseLimit(true)
+		case "OFFSET":
+			q.Offset, err = p.parseLimit(false)
+		case "ORDER BY":
+			if err = p.expect("BY"); err == nil {
+				q.OrderBy, err = p.parseOrderBy()
+			}
+		case "WITH TIMEZONE":
+			if err = p.expect("TIMEZONE"); err == nil {
+				q.Timezone, err = p.parseString()
+			}
+		case "EXTRAPOLATE":
+			q.Extrapolate = true
+		case "RAW":
+			q.Raw = true
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if q.ShowEventTypes {
+		return q, nil
+	}
+
+	for _, clause := range []string{"SELECT", "FROM"} {
+		if !seen[clause] {
+			return nil, p.missingClause(start, clause)
+		}
+	}
+
+	return q, nil
+}
+
+// skipClause skips an unknown word and what follows it, up to the next clause
+// or the end of the query it is in.
+func (p *parser) skipClause() {
+	p.next()
+
+	depth := 0
+	for {
+		t := p.peek()
+
+		switch {
+		case t.kind == tokenEOF:
+			return
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			if depth == 0 {
+				return
+			}
+			depth--
+		case depth == 0 && t.kind == tokenWord && clauses[strings.ToUpper(t.text)] != "":
+			return
+		}
+
+		p.next()
+	}
+}
+
+// missingClause returns the error of a query without a clause it requires,
+// which points at the unknown word that is likely the clause misspelled, if
+// any.
+func (p *parser) missingClause(start token, clause string) error {
+	for _, t := range p.unknown {
+		if suggestClause(t) == clause {
+			return &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unknown keyword %s, did you mean %s?", t, clause)}
+		}
+	}
+
+	return &SyntaxError{Pos: start.pos, Msg: fmt.Sprintf("expected a %s clause", clause)}
+}
+
+// unknownClause returns the error of an unknown word found where a clause was
+// expected.
+func unknownClause(t token) error {
+	msg := fmt.Sprintf("unknown keyword %s, expected a clause such as WHERE, FACET or SINCE", t)
+	if clause := suggestClause(t); clause != "" {
+		msg = fmt.Sprintf("unknown keyword %s, did you mean %s?", t, clause)
+	}
+
+	return &SyntaxError{Pos: t.pos, Msg: msg, Unknown: true}
+}
+
+// suggestClause returns the keyword of the clause a word is likely a
+// misspelling of, if any. Words may be a third of a keyword away from it.
+func suggestClause(t token) string {
+	word := strings.ToUpper(t.text)
+	suggested, best := "", 0
+
+	for keyword := range clauses {
+		d := editDistance(word, keyword)
+		if d == 0 || d > len(keyword)/3 {
+			continue
+		}
+
+		if suggested == "" || d < best || (d == best && keyword < suggested) {
+			suggested, best = keyword, d
+		}
+	}
+
+	return suggested
+}
+
+// editDistance returns the number of letters to insert, delete, replace or
+// swap with their neighbour to turn one word into the other.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = minInt(d[i-1][j]+1, minInt(d[i][j-1]+1, d[i-1][j-1]+cost))
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(a)][len(b)]
+}
+
+func (p *parser) parseSelect() ([]*SelectItem, error) {
+	if p.accept("*") {
+		return []*SelectItem{{Expr: &Ident{Name: "*"}}}, nil
+	}
+
+	return p.parseSelectItems()
+}
+
+// parseSelectItems parses a list of expressions, each with an optional alias.
+func (p *parser) parseSelectItems() ([]*SelectItem, error) {
+	items := []*SelectItem{}
+
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		alias, err := p.parseAlias()
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, &SelectItem{Expr: e, Alias: alias})
+
+		if !p.accept(",") {
+			return items, nil
+		}
+	}
+}
+
+// parseAlias parses an optional AS clause.
+func (p *parser) parseAlias() (string, error) {
+	if !p.accept("AS") {
+		return "", nil
+	}
+
+	t := p.peek()
+	if t.kind == tokenString || t.kind == tokenQuotedIdent || (t.kind == tokenWord && !isReserved(t)) {
+		p.next()
+		return t.value, nil
+	}
+
+	return "", p.errorf("expected an alias after AS, got %s", t)
+}
+
+func (p *parser) parseFrom() ([]*FromItem, error) {
+	items := []*FromItem{}
+
+	for {
+		t := p.peek()
+
+		switch {
+		case t.is("("):
+			p.next()
+			if !p.peek().is("SELECT") && !p.peek().is("FROM") {
+				return nil, p.errorf("expected a nested query, got %s", p.peek())
+			}
+
+			q, err := p.parseQuery()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+
+			items = append(items, &FromItem{Query: q})
+		case t.kind == tokenQuotedIdent || (t.kind == tokenWord && !isReserved(t)):
+			p.next()
+			items = append(items, &FromItem{EventType: t.value})
+		default:
+			return nil, p.errorf("expected an event type, got %s", t)
+		}
+
+		if !p.accept(",") {
+			return items, nil
+		}
+	}
+}
+
+// parseTime parses the point in time of SINCE, UNTIL and COMPARE WITH.
+func (p *parser) parseTime() (*Time, error) {
+	t := p.peek()
+
+	switch {
+	case t.kind == tokenString:
+		p.next()
+		return &Time{Text: t.text}, nil
+
+	case t.kind == tokenNumber:
+		p.next()
+
+		// A number on its own is a timestamp in milliseconds.
+		if !isTimeUnit(p.peek()) {
+			return &Time{Text: t.text}, nil
+		}
+
+		unit := p.next()
+		if err := p.expect("AGO"); err != nil {
+			return nil, err
+		}
+
+		return &Time{Text: fmt.Sprintf("%s %s AGO", t.text, unit.text)}, nil
+
+	case t.is("NOW"), t.is("TODAY"), t.is("YESTERDAY"):
+		p.next()
+		return &Time{Text: strings.ToUpper(t.text)}, nil
+
+	case t.kind == tokenInvalidNumber:
+		return nil, p.errorf("expected a time such as '1 hour ago', got %s: units of time are written in full, after a space", t)
+
+	case t.is("-"):
+		return nil, p.errorf("expected a time such as '1 hour ago', got %s: times in the past are written with AGO rather than as negative numbers", t)
+
+	case t.is("THIS"), t.is("LAST"):
+		p.next()
+
+		unit := p.peek()
+		if !isTimeUnit(unit) {
+			return nil, p.errorf("expected a unit of time after %s, got %s", strings.ToUpper(t.text), unit)
+		}
+		p.next()
+
+		return &Time{Text: strings.ToUpper(t.text + " " + unit.text)}, nil
+	}
+
+	return nil, p.errorf("expected a time such as '1 day ago', got %s", t)
+}
+
+// parseTimeseries parses the optional bucket of a TIMESERIES clause and its
+// SLIDE BY window.
+func (p *parser) parseTimeseries() (*Timeseries, error) {
+	ts := &Timeseries{}
+
+	bucket, err := p.parseBucket(true)
+	if err != nil {
+		return nil, err
+	}
+	ts.Bucket = bucket
+
+	if p.accept("SLIDE") {
+		if err := p.expect("BY"); err != nil {
+			return nil, err
+		}
+
+		if ts.SlideBy, err = p.parseBucket(false); err != nil {
+			return nil, err
+		}
+	}
+
+	return ts, nil
+}
+
+// parseBucket parses a duration, such as `5 minutes`, or AUTO or MAX.
+func (p *parser) parseBucket(optional bool) (string, error) {
+	t := p.peek()
+
+	switch {
+	case t.is("AUTO"), t.is("MAX"):
+		p.next()
+		return strings.ToUpper(t.text), nil
+
+	case isTimeUnit(t):
+		p.next()
+		return "1 " + strings.ToUpper(t.text), nil
+
+	case t.kind == tokenInvalidNumber:
+		return "", p.errorf("expected a duration such as '5 minutes', got %s: units of time are written in full, after a space", t)
+
+	case t.kind == tokenNumber:
+		p.next()
+
+		unit := p.peek()
+		if !isTimeUnit(unit) {
+			return "", p.errorf("expected a unit of time after %s, got %s", t.text, unit)
+		}
+		p.next()
+
+		return t.text + " " + strings.ToUpper(unit.text), nil
+	}
+
+	if optional {
+		return "", nil
+	}
+
+	return "", p.errorf("expected a duration such as '5 minutes', got %s", t)
+}
+
+// parseLimit parses the value of LIMIT, which may be MAX, or of OFFSET.
+func (p *parser) parseLimit(max bool) (*Literal, error) {
+	t := p.peek()
+
+	if max && t.is("MAX") {
+		p.next()
+		return &Literal{Kind: String, Value: "MAX"}, nil
+	}
+
+	if t.kind != tokenNumber || strings.ContainsAny(t.text, ".eE") {
+		return nil, p.errorf("expected a whole number, got %s", t)
+	}
+	p.next()
+
+	return &Literal{Kind: Number, Value: t.text}, nil
+}
+
+func (p *parser) parseOrderBy() ([]*OrderItem, error) {
+	items := []*OrderItem{}
+
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		item := &OrderItem{Expr: e}
+		if p.accept("DESC") {
+			item.Descending = true
+		} else {
+			p.accept("ASC")
+		}
+
+		items = append(items, item)
+
+		if !p.accept(",") {
+			return items, nil
+		}
+	}
+}
+
+func (p *parser) parseString() (*Literal, error) {
+	t := p.peek()
+	if t.kind != tokenString {
+		return nil, p.errorf("expected a string, got %s", t)
+	}
+	p.next()
+
+	return &Literal{Kind: String, Value: t.value}, nil
+}
+
+// isTimeUnit reports whether a token is a unit of time, in the singular or
+// the plural.
+func isTimeUnit(t token) bool {
+	if t.kind != tokenWord {
+		return false
+	}
+
+	unit := strings.ToUpper(t.text)
+
+	return timeUnits[unit] || (strings.HasSuffix(unit, "S") && timeUnits[strings.TrimSuffix(unit, "S")])
+}
+
+// parseExpr parses an expression, from the operators that bind the least.
+func (p *parser) parseExpr() (Expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Binary{Op: "OR", Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Binary{Op: "AND", Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.accept("NOT") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Unary{Op: "NOT", X: x}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+
+	switch {
+	case t.kind == tokenSymbol && comparisons[t.text]:
+		p.next()
+
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Binary{Op: t.text, Left: left, Right: right}, nil
+
+	case t.is("IS"):
+		p.next()
+
+		op := "IS"
+		if p.accept("NOT") {
+			op = "IS NOT"
+		}
+
+		v := p.peek()
+		if !v.is("NULL") && !v.is("TRUE") && !v.is("FALSE") {
+			return nil, p.errorf("expected NULL, TRUE or FALSE after %s, got %s", op, v)
+		}
+		p.next()
+
+		return &Binary{Op: op, Left: left, Right: literalWord(v)}, nil
+	}
+
+	op := ""
+	if t.is("NOT") {
+		if n := p.peekAt(1); n.is("LIKE") || n.is("RLIKE") || n.is("IN") {
+			p.next()
+			op = "NOT "
+		}
+	}
+
+	switch t := p.peek(); {
+	case t.is("LIKE"), t.is("RLIKE"):
+		p.next()
+
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Binary{Op: op + strings.ToUpper(t.text), Left: left, Right: right}, nil
+
+	case t.is("IN"):
+		p.next()
+
+		right, err := p.parseInList()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Binary{Op: op + "IN", Left: left, Right: right}, nil
+	}
+
+	return left, nil
+}
+
+// parseInList parses the list of values on the right of IN, or a nested
+// query that returns them.
+func (p *parser) parseInList() (Expr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	if p.peek().is("SELECT") || p.peek().is("FROM") {
+		q, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+
+		return &Subquery{Query: q}, nil
+	}
+
+	list := &List{}
+
+	for {
+		e, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+
+		list.Items = append(list.Items, e)
+
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (p *parser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for t := p.peek(); t.is("+") || t.is("-"); t = p.peek() {
+		p.next()
+
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Binary{Op: t.text, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for t := p.peek(); t.is("*") || t.is("/") || t.is("%"); t = p.peek() {
+		p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Binary{Op: t.text, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if t := p.peek(); t.is("-") || t.is("+") {
+		p.next()
+
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Unary{Op: t.text, X: x}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.peek()
+
+	switch {
+	case t.kind == tokenNumber:
+		p.next()
+		return &Literal{Kind: Number, Value: t.text}, nil
+
+	case t.kind == tokenInvalidNumber:
+		return nil, p.errorf("invalid number %s", t)
+
+	case t.kind == tokenString:
+		p.next()
+		return &Literal{Kind: String, Value: t.value}, nil
+
+	case t.kind == tokenQuotedIdent:
+		p.next()
+		return &Ident{Name: t.value}, nil
+
+	case t.is("("):
+		p.next()
+
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+
+		return e, nil
+
+	case t.is("TRUE"), t.is("FALSE"), t.is("NULL"):
+		p.next()
+		return literalWord(t), nil
+
+	case t.kind == tokenWord && !isReserved(t):
+		p.next()
+
+		if p.peek().is("(") {
+			return p.parseCall(t)
+		}
+
+		return &Ident{Name: t.value}, nil
+	}
+
+	if t.kind == tokenEOF {
+		return nil, p.errorf("expected a value, got end of query")
+	}
+
+	return nil, p.errorf("expected a value, got %s", t)
+}
+
+// parseCall parses the arguments of a function. Arguments may be `*`, as in
+// count(*), conditions, as in filter(count(*), WHERE error IS TRUE), or named,
+// as in apdex(duration, t: 0.5).
+func (p *parser) parseCall(name token) (Expr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	call := &Call{Name: name.value}
+
+	if p.accept(")") {
+		return call, nil
+	}
+
+	for {
+		var arg Expr
+
+		switch {
+		case p.peek().is("*"):
+			p.next()
+			arg = &Ident{Name: "*"}
+
+		case p.peek().kind == tokenWord && p.peekAt(1).is(":"):
+			name := p.next()
+			p.next()
+
+			value, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+
+			arg = &NamedArg{Name: name.value, Value: value}
+
+		case p.accept("WHERE"):
+			where, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+
+			alias, err := p.parseAlias()
+			if err != nil {
+				return nil, err
+			}
+
+			arg = &Condition{Where: where, Alias: alias}
+
+		default:
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+
+			// Durations are given to functions such as rate(count(*), 1 minute).
+			if l, ok := e.(*Literal); ok && l.Kind == Number && isTimeUnit(p.peek()) {
+				e = &Literal{Kind: Duration, Value: l.Value + " " + strings.ToUpper(p.next().text)}
+			}
+
+			arg = e
+		}
+
+		call.Args = append(call.Args, arg)
+
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	if !p.peek().is(")") {
+		return nil, p.errorf("expected , or ) in the arguments of %s, got %s", name.value, p.peek())
+	}
+	p.next()
+
+	return call, nil
+}
+
+func literalWord(t token) *Literal {
+	switch strings.ToUpper(t.text) {
+	case "NULL":
+		return &Literal{Kind: Null, Value: "NULL"}
+	default:
+		return &Literal{Kind: Boolean, Value: strings.ToUpper(t.text)}
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
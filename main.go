@@ -9,4 +9,6 @@ import (
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: newrelic.Provider})
+
+	newrelic.ShutdownInstrumentation()
 }
@@ -0,0 +1,366 @@
+package newrelic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+)
+
+// apiErrorKind is the kind of failure an API error is recognized as.
+type apiErrorKind int
+
+const (
+	apiErrorUnknown apiErrorKind = iota
+	apiErrorNotFound
+	apiErrorUnauthorized
+	apiErrorForbidden
+	apiErrorInvalid
+)
+
+// apiError is an error returned by a New Relic API, along with what it was
+// doing and how it can likely be fixed.
+type apiError struct {
+	action string
+	object string
+	reason string
+	err    error
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("cannot %s %s: %s: %s", e.action, e.object, e.reason, e.err)
+}
+
+func (e *apiError) Unwrap() error {
+	return e.err
+}
+
+// Wraps the functions of a resource or data source so that the errors of New
+// Relic APIs they return say which resource, account and attribute were
+// involved, and what the likely fix is. Errors that are not recognized are
+// returned as they are.
+func explainAPIErrors(name string, r *schema.Resource) {
+	explain := func(action string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if f == nil {
+			return nil
+		}
+
+		return func(d *schema.ResourceData, meta interface{}) error {
+			err := f(d, meta)
+			if err == nil {
+				return nil
+			}
+
+			return explainAPIError(err, action, name, r, d, meta)
+		}
+	}
+
+	r.Create = explain("create", r.Create)
+	r.Read = explain("read", r.Read)
+	r.Update = explain("update", r.Update)
+	r.Delete = explain("delete", r.Delete)
+}
+
+// explainAPIError returns the error of an operation on a resource or data
+// source, explained if it is a recognized API error.
+func explainAPIError(err error, action string, name string, r *schema.Resource, d *schema.ResourceData, meta interface{}) error {
+	var explained *apiError
+	if errors.As(err, &explained) {
+		return err
+	}
+
+	classified := classifyAPIError(err)
+	if classified.kind == apiErrorUnknown {
+		return err
+	}
+
+	object := name
+	if d.Id() != "" {
+		object += " " + d.Id()
+	}
+
+	account := errorAccount(name, r, d, meta)
+	key := errorAPIKey(name, classified.api)
+
+	var reason string
+
+	switch classified.kind {
+	case apiErrorNotFound:
+		reason = fmt.Sprintf("it was not found in %s, it may have been deleted outside of Terraform or belong to another account", account)
+	case apiErrorUnauthorized:
+		reason = fmt.Sprintf("%s was rejected, check that it is valid and belongs to the region of %s", key, account)
+	case apiErrorForbidden:
+		reason = fmt.Sprintf("%s lacks access to %s, use a key of a user with access to it", key, account)
+	case apiErrorInvalid:
+		reason = "the API rejected its configuration, check the values of its arguments"
+		if attr := errorAttribute(r, classified); attr != "" {
+			reason = fmt.Sprintf("the API rejected the value of %s, check that it is valid", attr)
+		}
+	}
+
+	return &apiError{action: action, object: object, reason: reason, err: err}
+}
+
+// The APIs an error may come from.
+const (
+	errorAPIUnknown   = ""
+	errorAPINerdGraph = "nerdgraph"
+)
+
+// responseError is an error response of a New Relic API. newrelic-client-go
+// only returns the messages of error responses, so they are returned by
+// apiErrorTransport in place of the response instead, keeping their status
+// code and the codes of their NerdGraph errors.
+type responseError struct {
+	api        string
+	statusCode int
+	body       string
+	errors     []nerdGraphError
+}
+
+// nerdGraphError is one of the errors of a NerdGraph response.
+type nerdGraphError struct {
+	Message    string        `json:"message"`
+	Path       []interface{} `json:"path"`
+	Extensions struct {
+		Code             string `json:"code"`
+		ErrorClass       string `json:"errorClass"`
+		ValidationErrors []struct {
+			Name   string `json:"name"`
+			Reason string `json:"reason"`
+		} `json:"validationErrors"`
+	} `json:"extensions"`
+	DownstreamResponse []struct {
+		Message    string `json:"message"`
+		Extensions struct {
+			Code string `json:"code"`
+		} `json:"extensions"`
+	} `json:"downstreamResponse"`
+}
+
+func (e *responseError) Error() string {
+	if len(e.errors) == 0 {
+		msg := fmt.Sprintf("%d response returned", e.statusCode)
+		if e.body != "" {
+			msg += ": " + e.body
+		}
+
+		return msg
+	}
+
+	messages := make([]string, 0, len(e.errors))
+	for _, err := range e.errors {
+		message := err.Message
+		for _, v := range err.Extensions.ValidationErrors {
+			message += fmt.Sprintf(" (%s: %s)", v.Name, v.Reason)
+		}
+		for _, r := range err.DownstreamResponse {
+			message += fmt.Sprintf(" (%s)", r.Message)
+		}
+
+		messages = append(messages, message)
+	}
+
+	return strings.Join(messages, ", ")
+}
+
+// classify returns what is known of the error from its status code and the
+// codes and error classes of its NerdGraph errors. The codes of downstream
+// responses are not relied on, as NerdGraph relays them from other APIs,
+// which use them for more than one kind of failure.
+func (e *responseError) classify() classifiedAPIError {
+	c := classifiedAPIError{kind: statusCodeKind(e.statusCode), api: e.api}
+
+	for _, err := range e.errors {
+		for _, v := range err.Extensions.ValidationErrors {
+			c.fields = append(c.fields, v.Name)
+		}
+
+		for _, code := range []string{err.Extensions.ErrorClass, err.Extensions.Code} {
+			if c.kind != apiErrorUnknown {
+				break
+			}
+
+			switch code {
+			case "NOT_FOUND":
+				c.kind = apiErrorNotFound
+			case "UNAUTHENTICATED", "UNAUTHORIZED":
+				c.kind = apiErrorUnauthorized
+			case "FORBIDDEN":
+				c.kind = apiErrorForbidden
+			case "BAD_USER_INPUT", "INVALID_INPUT", "GRAPHQL_VALIDATION_FAILED":
+				c.kind = apiErrorInvalid
+			}
+		}
+	}
+
+	return c
+}
+
+// apiErrorTransport returns the error responses of New Relic APIs as
+// responseErrors, so that they can be told apart by their status codes and
+// the codes of their NerdGraph errors. Responses that are not found are left
+// to newrelic-client-go, which returns its NotFound error for them, and so
+// are NerdGraph errors whose codes are not recognized.
+type apiErrorTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *apiErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode == http.StatusNotFound {
+		return resp, err
+	}
+
+	api := errorAPIUnknown
+	if strings.HasSuffix(req.URL.Path, "/graphql") {
+		api = errorAPINerdGraph
+	}
+
+	success := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if success && api != errorAPINerdGraph {
+		return resp, nil
+	}
+
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return resp, nil
+	}
+
+	respErr := &responseError{api: api, statusCode: resp.StatusCode}
+
+	if api == errorAPINerdGraph {
+		var decoded struct {
+			Errors []nerdGraphError `json:"errors"`
+		}
+		_ = json.Unmarshal(body, &decoded)
+		respErr.errors = decoded.Errors
+	}
+
+	if len(respErr.errors) == 0 {
+		respErr.body = strings.TrimSpace(string(body))
+	}
+
+	if !success {
+		return nil, respErr
+	}
+
+	switch respErr.classify().kind {
+	case apiErrorUnknown:
+		return resp, nil
+	case apiErrorNotFound:
+		// NerdGraph answers queries for missing objects with errors, which
+		// newrelic-client-go does not tell apart from others, so they are
+		// turned into the not found responses of the REST APIs.
+		resp.StatusCode = http.StatusNotFound
+		resp.Status = "404 Not Found"
+		return resp, nil
+	}
+
+	return nil, respErr
+}
+
+// unsuccessfulResponse returns whether an error is that of a response whose
+// status code is not a successful one.
+func unsuccessfulResponse(err error) bool {
+	var respErr *responseError
+	return errors.As(err, &respErr) && (respErr.statusCode < 200 || respErr.statusCode >= 300)
+}
+
+// classifiedAPIError is what is known of an API error.
+type classifiedAPIError struct {
+	kind apiErrorKind
+	api  string
+	// The attributes named by the validation errors of NerdGraph, as the
+	// dotted path of the input they were found in.
+	fields []string
+}
+
+// classifyAPIError returns what is known of an API error, which is only what
+// its type, status code or NerdGraph error codes say. The messages of errors
+// are not relied on, as they may change at any time.
+func classifyAPIError(err error) classifiedAPIError {
+	var notFound *nrErrors.NotFound
+	if errors.As(err, &notFound) {
+		return classifiedAPIError{kind: apiErrorNotFound}
+	}
+
+	var respErr *responseError
+	if errors.As(err, &respErr) {
+		return respErr.classify()
+	}
+
+	return classifiedAPIError{}
+}
+
+// statusCodeKind returns the kind of error an HTTP status code stands for.
+func statusCodeKind(status int) apiErrorKind {
+	switch status {
+	case 401:
+		return apiErrorUnauthorized
+	case 403:
+		return apiErrorForbidden
+	case 404:
+		return apiErrorNotFound
+	case 400, 422:
+		return apiErrorInvalid
+	}
+
+	return apiErrorUnknown
+}
+
+// errorAPIKey returns the provider argument holding the key used with the API
+// an error came from.
+func errorAPIKey(name string, api string) string {
+	switch {
+	case name == "newrelic_insights_event":
+		return "insights_insert_key"
+	case api == errorAPINerdGraph:
+		return "personal_api_key"
+	}
+
+	return "api_key or personal_api_key"
+}
+
+// errorAccount describes the account an error is about: the one of the
+// resource, else the one of the provider.
+func errorAccount(name string, r *schema.Resource, d *schema.ResourceData, meta interface{}) string {
+	if _, ok := r.Schema["account_id"]; ok {
+		if accountID, ok := d.GetOk("account_id"); ok {
+			return fmt.Sprintf("account %v", accountID)
+		}
+	}
+
+	if providerConfig, ok := meta.(*ProviderConfig); ok {
+		if name == "newrelic_insights_event" && providerConfig.clientConfig != nil && providerConfig.clientConfig.InsightsAccountID != "" {
+			return "account " + providerConfig.clientConfig.InsightsAccountID
+		}
+
+		if providerConfig.AccountID != 0 {
+			return fmt.Sprintf("account %d", providerConfig.AccountID)
+		}
+	}
+
+	return "the account"
+}
+
+// errorAttribute returns the top-level attribute of a resource named by the
+// validation errors of NerdGraph, whose names are the dotted path of the
+// invalid value in the input, such as `condition.incidentPreference`.
+func errorAttribute(r *schema.Resource, classified classifiedAPIError) string {
+	for _, field := range classified.fields {
+		for _, part := range strings.Split(field, ".") {
+			for k := range r.Schema {
+				if strings.EqualFold(strings.Replace(k, "_", "", -1), part) {
+					return k
+				}
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,234 @@
+package newrelic
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func testAPIErrorResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":                {Type: schema.TypeString, Required: true},
+			"incident_preference": {Type: schema.TypeString, Optional: true},
+		},
+	}
+}
+
+func TestExplainAPIError(t *testing.T) {
+	r := testAPIErrorResource()
+	meta := &ProviderConfig{AccountID: 123}
+
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{
+			err:  nrErrors.NewNotFound("resource not found"),
+			want: "cannot update newrelic_alert_policy 42: it was not found in account 123, it may have been deleted outside of Terraform or belong to another account: resource not found",
+		},
+		{
+			err:  &responseError{statusCode: 401, body: `{"error":{"title":"The API key provided is invalid"}}`},
+			want: "cannot update newrelic_alert_policy 42: api_key or personal_api_key was rejected, check that it is valid and belongs to the region of account 123: 401 response returned: {\"error\":{\"title\":\"The API key provided is invalid\"}}",
+		},
+		{
+			err:  &responseError{statusCode: 403},
+			want: "cannot update newrelic_alert_policy 42: api_key or personal_api_key lacks access to account 123, use a key of a user with access to it: 403 response returned",
+		},
+		{
+			// Attributes are only named by structured errors.
+			err:  &responseError{statusCode: 422, body: "Validation Error: Name can't be blank"},
+			want: "cannot update newrelic_alert_policy 42: the API rejected its configuration, check the values of its arguments: 422 response returned: Validation Error: Name can't be blank",
+		},
+		{
+			err:  &responseError{statusCode: 500, body: "Internal Server Error"},
+			want: "500 response returned: Internal Server Error",
+		},
+		{
+			// Neither are the errors newrelic-client-go makes of them.
+			err:  nrErrors.NewUnexpectedStatusCode(403, "Forbidden"),
+			want: "403 response returned: Forbidden",
+		},
+		{
+			// Messages are not relied on, whatever they say.
+			err:  errors.New("Account 456 not authorized, null"),
+			want: "Account 456 not authorized, null",
+		},
+	}
+
+	for _, tc := range cases {
+		d := r.Data(nil)
+		d.SetId("42")
+
+		err := explainAPIError(tc.err, "update", "newrelic_alert_policy", r, d, meta)
+		require.EqualError(t, err, tc.want)
+		require.True(t, errors.Is(err, tc.err))
+	}
+}
+
+// The errors of NerdGraph responses, as sent by NerdGraph, reach the provider
+// through a client and are explained from their codes.
+func TestExplainAPIError_NerdGraph(t *testing.T) {
+	cases := []struct {
+		status int
+		body   string
+		want   string
+	}{
+		{
+			status: http.StatusOK,
+			body:   `{"data":{"actor":{"account":{"alerts":{"nrqlCondition":null}}}},"errors":[{"extensions":{"errorClass":"NOT_FOUND"},"locations":[{"column":42,"line":1}],"message":"Not Found","path":["actor","account","alerts","nrqlCondition"]}]}`,
+			want:   "cannot read newrelic_alert_policy 42: it was not found in account 123, it may have been deleted outside of Terraform or belong to another account: resource not found",
+		},
+		{
+			status: http.StatusOK,
+			body:   `{"data":{"actor":{"account":null}},"errors":[{"extensions":{"errorClass":"FORBIDDEN"},"locations":[{"column":11,"line":1}],"message":"Access denied.","path":["actor","account"]}]}`,
+			want:   "cannot read newrelic_alert_policy 42: personal_api_key lacks access to account 123, use a key of a user with access to it: Post %q: Access denied.",
+		},
+		{
+			status: http.StatusUnauthorized,
+			body:   `{"errors":[{"message":"Invalid API key"}]}`,
+			want:   "cannot read newrelic_alert_policy 42: personal_api_key was rejected, check that it is valid and belongs to the region of account 123: Post %q: Invalid API key",
+		},
+		{
+			status: http.StatusOK,
+			body:   `{"data":{"alertsPolicyCreate":null},"errors":[{"extensions":{"errorClass":"INVALID_INPUT","validationErrors":[{"name":"policy.incidentPreference","reason":"is not a valid incident preference"}]},"locations":[{"column":3,"line":2}],"message":"Validation Error","path":["alertsPolicyCreate"]}]}`,
+			want:   "cannot read newrelic_alert_policy 42: the API rejected the value of incident_preference, check that it is valid: Post %q: Validation Error (policy.incidentPreference: is not a valid incident preference)",
+		},
+		{
+			// The codes of downstream responses are left to
+			// newrelic-client-go.
+			status: http.StatusOK,
+			body:   `{"data":{"alertsNrqlConditionStaticUpdate":null},"errors":[{"downstreamResponse":[{"extensions":{"code":"BAD_USER_INPUT"},"message":"Not Found"}],"message":"Bad Request","path":["alertsNrqlConditionStaticUpdate"]}]}`,
+			want:   `Bad Request, [{"extensions":{"code":"BAD_USER_INPUT"},"message":"Not Found"}]`,
+		},
+		{
+			// Errors without a recognized code are returned by
+			// newrelic-client-go as they are, whatever their message names.
+			status: http.StatusOK,
+			body:   `{"errors":[{"locations":[{"column":29,"line":1}],"message":"Argument \"policy\" has invalid value $policy.\nIn field \"incidentPreference\": Expected type \"AlertsIncidentPreference!\", found \"FOO\"."}]}`,
+			want:   "Argument \"policy\" has invalid value $policy.\nIn field \"incidentPreference\": Expected type \"AlertsIncidentPreference!\", found \"FOO\"., null",
+		},
+		{
+			status: http.StatusOK,
+			body:   `{"data":null,"errors":[{"extensions":{"errorClass":"SERVER_ERROR"},"message":"Something went wrong, account 456 is not available"}]}`,
+			want:   "Something went wrong, account 456 is not available, null",
+		},
+	}
+
+	r := testAPIErrorResource()
+	meta := &ProviderConfig{AccountID: 123}
+
+	for _, tc := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(tc.status)
+			_, _ = w.Write([]byte(tc.body))
+		}))
+
+		cfg := Config{
+			PersonalAPIKey:  "NRAK-abc123",
+			NerdGraphAPIURL: srv.URL + "/graphql",
+			userAgent:       "terraform-provider-newrelic-test",
+		}
+
+		client, err := cfg.Client()
+		require.NoError(t, err)
+
+		_, err = client.NerdGraph.Query(`{ actor { user { id } } }`, nil)
+		require.Error(t, err)
+
+		d := r.Data(nil)
+		d.SetId("42")
+
+		require.EqualError(t, explainAPIError(err, "read", "newrelic_alert_policy", r, d, meta), strings.Replace(tc.want, "%q", strconv.Quote(srv.URL+"/graphql"), 1), tc.body)
+		srv.Close()
+	}
+}
+
+func TestExplainAPIErrors(t *testing.T) {
+	r := &schema.Resource{
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			return &responseError{statusCode: 403, body: `Not authorized, null`}
+		},
+		Schema: map[string]*schema.Schema{
+			"account_id": {Type: schema.TypeInt, Required: true},
+		},
+	}
+	explainAPIErrors("newrelic_workload", r)
+
+	d := r.Data(nil)
+	require.NoError(t, d.Set("account_id", 789))
+
+	err := r.Create(d, &ProviderConfig{AccountID: 123})
+	require.EqualError(t, err, "cannot create newrelic_workload: api_key or personal_api_key lacks access to account 789, use a key of a user with access to it: 403 response returned: Not authorized, null")
+
+	// Errors are only explained once.
+	require.Equal(t, err, explainAPIError(err, "create", "newrelic_workload", r, d, nil))
+
+	var respErr *responseError
+	require.True(t, errors.As(err, &respErr))
+}
+
+func TestAPIErrorTransport(t *testing.T) {
+	cases := []struct {
+		path   string
+		status int
+		body   string
+		// The status code of the response returned, if any.
+		wantStatus int
+		// The error returned in place of the response, if any.
+		wantErr *responseError
+	}{
+		{path: "/v2/alerts_policies.json", status: http.StatusOK, body: `{"policies":[]}`, wantStatus: http.StatusOK},
+		{path: "/v2/alerts_policies/1.json", status: http.StatusNotFound, body: `{"error":{"title":"Not Found"}}`, wantStatus: http.StatusNotFound},
+		{
+			path:    "/v2/alerts_policies.json",
+			status:  http.StatusUnprocessableEntity,
+			body:    "{\"error\":{\"title\":\"Name can't be blank\"}}\n",
+			wantErr: &responseError{statusCode: http.StatusUnprocessableEntity, body: `{"error":{"title":"Name can't be blank"}}`},
+		},
+		{path: "/graphql", status: http.StatusOK, body: `{"data":{"actor":{"user":{"id":1}}}}`, wantStatus: http.StatusOK},
+		{path: "/graphql", status: http.StatusOK, body: `{"errors":[{"extensions":{"errorClass":"NOT_FOUND"},"message":"Not Found"}]}`, wantStatus: http.StatusNotFound},
+		{path: "/graphql", status: http.StatusOK, body: `{"errors":[{"extensions":{"errorClass":"SERVER_ERROR"},"message":"Something went wrong"}]}`, wantStatus: http.StatusOK},
+		{
+			path:    "/graphql",
+			status:  http.StatusBadGateway,
+			body:    "<html>Bad Gateway</html>",
+			wantErr: &responseError{api: errorAPINerdGraph, statusCode: http.StatusBadGateway, body: "<html>Bad Gateway</html>"},
+		},
+	}
+
+	for _, tc := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+			_, _ = w.Write([]byte(tc.body))
+		}))
+
+		req, err := http.NewRequest("POST", srv.URL+tc.path, nil)
+		require.NoError(t, err)
+
+		resp, err := (&apiErrorTransport{transport: http.DefaultTransport}).RoundTrip(req)
+		srv.Close()
+
+		if tc.wantErr != nil {
+			require.Equal(t, tc.wantErr, err, tc.body)
+			continue
+		}
+
+		require.NoError(t, err, tc.body)
+		require.Equal(t, tc.wantStatus, resp.StatusCode, tc.body)
+
+		// The body is left for the client to read.
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, tc.body, string(body))
+	}
+}
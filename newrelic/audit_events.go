@@ -0,0 +1,145 @@
+package newrelic
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+	"github.com/terraform-providers/terraform-provider-newrelic/version"
+)
+
+// The event type of audit events, unless another is configured.
+const defaultAuditEventType = "TerraformChange"
+
+// auditEvents describes the Insights events posted for every resource the
+// provider creates, updates or deletes.
+type auditEvents struct {
+	eventType        string
+	terraformVersion string
+}
+
+// expandAuditEvents returns the audit events configured by the provider's
+// `audit_events` block, or nil when they are not enabled.
+func expandAuditEvents(cfg []interface{}, terraformVersion string) *auditEvents {
+	if len(cfg) == 0 || cfg[0] == nil {
+		return nil
+	}
+
+	block := cfg[0].(map[string]interface{})
+	if !block["enabled"].(bool) {
+		return nil
+	}
+
+	return &auditEvents{
+		eventType:        block["event_type"].(string),
+		terraformVersion: terraformVersion,
+	}
+}
+
+// event returns the audit event of a change to a resource.
+func (a *auditEvents) event(action string, resourceType string, id string, accountID int, changed []string) map[string]interface{} {
+	return map[string]interface{}{
+		"eventType":         a.eventType,
+		"action":            action,
+		"resourceType":      resourceType,
+		"resourceId":        id,
+		"accountId":         accountID,
+		"changedAttributes": strings.Join(changed, ","),
+		"terraformVersion":  a.terraformVersion,
+		"providerVersion":   version.ProviderVersion,
+	}
+}
+
+// Wraps the Create, Update and Delete functions of a resource so that each
+// successful change is posted to Insights as an audit event, when the provider
+// is configured to do so.
+//
+// Events hold the names of the attributes set or changed, but never their
+// values, so that sensitive values are not sent to Insights.
+func auditChanges(name string, r *schema.Resource) {
+	audit := func(action string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if f == nil {
+			return nil
+		}
+
+		return func(d *schema.ResourceData, meta interface{}) error {
+			providerConfig, ok := meta.(*ProviderConfig)
+			if !ok || providerConfig.auditEvents == nil {
+				return f(d, meta)
+			}
+
+			// Changes are known before the operation, after which the
+			// resource holds the state read back from the API.
+			changed := []string{}
+			if action != "delete" {
+				changed = changedAttributes(r, d)
+			}
+
+			id := d.Id()
+
+			if err := f(d, meta); err != nil {
+				return err
+			}
+
+			if id == "" {
+				id = d.Id()
+			}
+
+			event := providerConfig.auditEvents.event(action, name, id, auditAccountID(providerConfig, r, d), changed)
+
+			// The change has been made by now, so failing to record it
+			// must not fail the operation.
+			if err := postAuditEvent(providerConfig, event); err != nil {
+				log.Printf("[WARN] failed to post audit event for %s %s: %s", name, id, err)
+			}
+
+			return nil
+		}
+	}
+
+	r.Create = audit("create", r.Create)
+	r.Update = audit("update", r.Update)
+	r.Delete = audit("delete", r.Delete)
+}
+
+func postAuditEvent(providerConfig *ProviderConfig, event map[string]interface{}) error {
+	client, cancel, err := providerConfig.insightsInsertClient(requestTimeout)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return client.PostEvent(event)
+}
+
+// changedAttributes returns the sorted names of the configurable attributes of
+// a resource that are being set or changed.
+func changedAttributes(r *schema.Resource, d *schema.ResourceData) []string {
+	changed := []string{}
+
+	for k, s := range r.Schema {
+		if !s.Optional && !s.Required {
+			continue
+		}
+
+		if d.HasChange(k) {
+			changed = append(changed, k)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return changed
+}
+
+// auditAccountID returns the account a resource belongs to, which is that of
+// the provider unless the resource has an account of its own.
+func auditAccountID(providerConfig *ProviderConfig, r *schema.Resource, d *schema.ResourceData) int {
+	if s, ok := r.Schema["account_id"]; ok && s.Type == schema.TypeInt {
+		return selectAccountID(providerConfig, d)
+	}
+
+	return providerConfig.AccountID
+}
@@ -0,0 +1,93 @@
+package newrelic
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func testAuditEventsServer(t *testing.T) (*httptest.Server, *[]map[string]interface{}) {
+	events := []map[string]interface{}{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+
+		event := map[string]interface{}{}
+		require.NoError(t, json.NewDecoder(body).Decode(&event))
+		events = append(events, event)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+
+	return srv, &events
+}
+
+func TestAuditChanges(t *testing.T) {
+	srv, events := testAuditEventsServer(t)
+	defer srv.Close()
+
+	meta := &ProviderConfig{
+		AccountID: mockAccountID,
+		clientConfig: &Config{
+			InsightsAccountID: "1",
+			InsightsInsertKey: "insert-key",
+			InsightsInsertURL: srv.URL + "/v1/accounts",
+			userAgent:         "terraform-provider-newrelic-test",
+		},
+		auditEvents: expandAuditEvents([]interface{}{map[string]interface{}{"enabled": true, "event_type": "TerraformChange"}}, "0.12.29"),
+	}
+
+	r := &schema.Resource{
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			d.SetId("42")
+			return nil
+		},
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"name":     {Type: schema.TypeString, Required: true, ForceNew: true},
+			"password": {Type: schema.TypeString, Optional: true, ForceNew: true, Sensitive: true},
+			"enabled":  {Type: schema.TypeBool, Optional: true, ForceNew: true},
+			"guid":     {Type: schema.TypeString, Computed: true},
+		},
+	}
+	auditChanges("newrelic_test", r)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":     "checkout",
+		"password": "hunter2",
+	})
+
+	require.NoError(t, r.Create(d, meta))
+	require.NoError(t, r.Delete(d, meta))
+
+	require.Len(t, *events, 2)
+	require.Equal(t, map[string]interface{}{
+		"eventType":         "TerraformChange",
+		"action":            "create",
+		"resourceType":      "newrelic_test",
+		"resourceId":        "42",
+		"accountId":         float64(mockAccountID),
+		"changedAttributes": "name,password",
+		"terraformVersion":  "0.12.29",
+		"providerVersion":   "dev",
+	}, (*events)[0])
+	require.Equal(t, "delete", (*events)[1]["action"])
+	require.Equal(t, "", (*events)[1]["changedAttributes"])
+}
+
+func TestAuditChanges_Disabled(t *testing.T) {
+	require.Nil(t, expandAuditEvents([]interface{}{}, "0.12.29"))
+	require.Nil(t, expandAuditEvents([]interface{}{map[string]interface{}{"enabled": false, "event_type": "TerraformChange"}}, "0.12.29"))
+}
@@ -1,28 +1,54 @@
 package newrelic
 
 import (
-	"compress/gzip"
+	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
 
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/logging"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/mutexkv"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/pathorcontents"
-	insights "github.com/newrelic/go-insights/client"
+	agent "github.com/newrelic/go-agent/v3/newrelic"
 	nr "github.com/newrelic/newrelic-client-go/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/dashboards"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdgraph"
+	"golang.org/x/net/http/httpproxy"
 )
 
 const serviceName = "terraform-provider-newrelic"
 
+const (
+	defaultMaxRetries   = 3
+	defaultRetryMaxWait = 30 * time.Second
+	retryMinWait        = 1 * time.Second
+
+	// requestTimeout bounds each attempt made by the retry transport.
+	requestTimeout = 30 * time.Second
+)
+
 // Config contains New Relic provider settings
 type Config struct {
 	AdminAPIKey          string
 	PersonalAPIKey       string
 	APIURL               string
 	CACertFile           string
+	ClientCertFile       string
+	ClientKeyFile        string
 	InfrastructureAPIURL string
 	InsecureSkipVerify   bool
 	InsightsAccountID    string
@@ -30,13 +56,36 @@ type Config struct {
 	InsightsInsertURL    string
 	InsightsQueryKey     string
 	InsightsQueryURL     string
+	Instrumented         bool
+	MaxRetries           int
 	NerdGraphAPIURL      string
+	NoProxy              string
+	ProxyURL             string
+	RateLimiter          *RateLimiter
+	RetryMaxWait         time.Duration
+	SensitiveFields      map[string][]string // redacted from logged payloads, as returned by sensitiveAPIFields
 	SyntheticsAPIURL     string
+	listCache            *listCache        // shared with the ProviderConfig, and cleared by every change made through the client
+	transport            http.RoundTripper // built on first use, by providerConfigure before any resource runs
 	userAgent            string
 }
 
 // Client returns a new client for accessing New Relic
 func (c *Config) Client() (*nr.NewRelic, error) {
+	client, err := c.ClientWithContext(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[INFO] New Relic client configured")
+
+	return client, nil
+}
+
+// ClientWithContext returns a new client for accessing New Relic whose
+// requests, and any waits for a retry or the rate limiter, are abandoned once
+// ctx is done.
+func (c *Config) ClientWithContext(ctx context.Context) (*nr.NewRelic, error) {
 	options := []nr.ConfigOption{}
 
 	options = append(options,
@@ -46,32 +95,28 @@ func (c *Config) Client() (*nr.NewRelic, error) {
 		nr.ConfigServiceName(serviceName),
 	)
 
-	tlsCfg := &tls.Config{}
-	var t = http.DefaultTransport
+	t, err := c.sharedTransport()
+	if err != nil {
+		return nil, err
+	}
 
-	if c.CACertFile != "" {
-		caCert, _, err := pathorcontents.Read(c.CACertFile)
-		if err != nil {
-			log.Printf("Error reading CA Cert: %s", err)
-			return nil, err
+	// The client logs request headers and bodies as they are at trace
+	// level, so it is kept to debug and the details are logged, redacted, by
+	// the transport instead.
+	if level := logging.LogLevel(); level != "" {
+		if level == "TRACE" {
+			level = "DEBUG"
 		}
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM([]byte(caCert))
-		tlsCfg.RootCAs = caCertPool
-
-		t = &http.Transport{TLSClientConfig: tlsCfg}
-	} else if c.InsecureSkipVerify {
-		tlsCfg.InsecureSkipVerify = true
 
-		t = &http.Transport{TLSClientConfig: tlsCfg}
+		options = append(options, nr.ConfigLogLevel(level))
 	}
 
-	if logging.LogLevel() != "" {
-		options = append(options, nr.ConfigLogLevel(logging.LogLevel()))
-		t = logging.NewTransport("newrelic", t)
-	}
-
-	options = append(options, nr.ConfigHTTPTransport(t))
+	// The client's own timeout would span every retry of a request,
+	// so attempts are timed out by the retry transport instead.
+	options = append(options,
+		nr.ConfigHTTPTransport(clientTransport(&contextTransport{transport: &listCacheTransport{transport: t, cache: c.listCache}, ctx: ctx})),
+		nr.ConfigHTTPTimeout(0),
+	)
 
 	if c.APIURL != "" {
 		options = append(options, nr.ConfigBaseURL(c.APIURL))
@@ -90,75 +135,660 @@ func (c *Config) Client() (*nr.NewRelic, error) {
 	}
 
 	client, err := nr.New(options...)
-
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("[INFO] New Relic client configured")
+	if err := disableClientRetries(reflect.ValueOf(client).Elem()); err != nil {
+		return nil, err
+	}
 
 	return client, nil
 }
 
-// ClientInsightsInsert returns a new Insights insert client
-func (c *Config) ClientInsightsInsert() (*insights.InsertClient, error) {
-	client := insights.NewInsertClient(c.InsightsInsertKey, c.InsightsAccountID)
+var retryableClientType = reflect.TypeOf((*retryablehttp.Client)(nil))
+
+// disableClientRetries stops the HTTP clients of every API held by v from
+// retrying requests, so that requests are only ever retried by the retry
+// transport and max_retries is the total number of retries. The client library
+// has no option for it, so its clients are found among the unexported fields
+// of each API. An API whose client cannot be found is an error, as its
+// retries would otherwise add up with those of the transport.
+func disableClientRetries(v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() != reflect.Struct {
+			continue
+		}
+
+		if disableRetries(v.Field(i)) == 0 {
+			return fmt.Errorf("cannot turn off the retries of the %s client of newrelic-client-go", v.Type().Field(i).Name)
+		}
+	}
+
+	return nil
+}
+
+// disableRetries stops the HTTP clients held by v from retrying requests, and
+// returns how many it found.
+func disableRetries(v reflect.Value) int {
+	found := 0
+
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+
+		switch {
+		case f.Type() == retryableClientType:
+			// Unexported fields cannot be set through reflect alone.
+			f = reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+			if c, ok := f.Interface().(*retryablehttp.Client); ok && c != nil {
+				c.RetryMax = 0
+				c.CheckRetry = func(context.Context, *http.Response, error) (bool, error) {
+					return false, nil
+				}
+				found++
+			}
+		case f.Kind() == reflect.Struct:
+			found += disableRetries(f)
+		}
+	}
 
-	if c.InsightsInsertURL != "" {
-		insightsURL, err := url.Parse(c.InsightsInsertURL)
+	return found
+}
+
+// sharedTransport returns the transport shared by every client made from the
+// config, building it on first use.
+func (c *Config) sharedTransport() (http.RoundTripper, error) {
+	if c.transport == nil {
+		t, err := c.newTransport()
 		if err != nil {
-			return nil, fmt.Errorf("error parsing Insights URL: %q", err)
+			return nil, err
 		}
-		insightsURL.Path = fmt.Sprintf("%s/%s/events", insightsURL.Path, c.InsightsAccountID)
-		client.URL = insightsURL
+
+		c.transport = t
 	}
 
-	client.SetCompression(gzip.DefaultCompression)
+	return c.transport, nil
+}
+
+// newTransport builds the transport shared by every client made from the
+// config, so that all of them retry and are rate limited the same way.
+func (c *Config) newTransport() (http.RoundTripper, error) {
+	t, err := c.tlsTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	if logging.LogLevel() != "" {
+		t = newLoggingTransport("newrelic", t, c.SensitiveFields)
+	}
+
+	if c.RateLimiter != nil {
+		t = &rateLimitTransport{transport: t, limiter: c.RateLimiter}
+	}
+
+	t = newRetryTransport(t, c.MaxRetries, c.RetryMaxWait)
+	t = &apiErrorTransport{transport: t}
+
+	// Requests are recorded once, however many times they are retried.
+	if c.Instrumented {
+		t = agent.NewRoundTripper(t)
+	}
+
+	return t, nil
+}
+
+// tlsTransport returns the transport that sends requests over the wire,
+// through the configured proxy, trusting the configured CA certificate and
+// presenting the configured client certificate, if any.
+func (c *Config) tlsTransport() (http.RoundTripper, error) {
+	if c.CACertFile == "" && !c.InsecureSkipVerify && c.ClientCertFile == "" && c.ClientKeyFile == "" && c.ProxyURL == "" && c.NoProxy == "" {
+		return http.DefaultTransport, nil
+	}
+
+	tlsCfg := &tls.Config{}
+
+	if c.CACertFile != "" {
+		caCert, _, err := pathorcontents.Read(c.CACertFile)
+		if err != nil {
+			log.Printf("Error reading CA Cert: %s", err)
+			return nil, err
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM([]byte(caCert))
+		tlsCfg.RootCAs = caCertPool
+	} else if c.InsecureSkipVerify {
+		tlsCfg.InsecureSkipVerify = true
+	}
 
-	if len(c.InsightsInsertKey) > 1 {
-		if err := client.Validate(); err != nil {
+	if c.ClientCertFile != "" || c.ClientKeyFile != "" {
+		cert, err := c.clientCertificate()
+		if err != nil {
 			return nil, err
 		}
+
+		tlsCfg.Certificates = []tls.Certificate{cert}
 	}
 
-	log.Printf("[INFO] New Relic Insights insert client configured")
+	proxy, err := c.proxy()
+	if err != nil {
+		return nil, err
+	}
 
-	return client, nil
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = tlsCfg
+	t.Proxy = proxy
+
+	return t, nil
+}
+
+// clientCertificate returns the certificate presented to servers asking for
+// one, read from the configured PEM-encoded certificate and key.
+func (c *Config) clientCertificate() (tls.Certificate, error) {
+	if c.ClientCertFile == "" || c.ClientKeyFile == "" {
+		return tls.Certificate{}, fmt.Errorf("client_cert_file and client_key_file must be set together")
+	}
+
+	cert, _, err := pathorcontents.Read(c.ClientCertFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("error reading client_cert_file: %w", err)
+	}
+
+	key, _, err := pathorcontents.Read(c.ClientKeyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("error reading client_key_file: %w", err)
+	}
+
+	pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid client certificate or key: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid client certificate: %w", err)
+	}
+
+	if now := time.Now(); now.After(leaf.NotAfter) {
+		return tls.Certificate{}, fmt.Errorf("invalid client certificate: expired on %s", leaf.NotAfter.Format(time.RFC3339))
+	} else if now.Before(leaf.NotBefore) {
+		return tls.Certificate{}, fmt.Errorf("invalid client certificate: not valid before %s", leaf.NotBefore.Format(time.RFC3339))
+	}
+
+	pair.Leaf = leaf
+
+	return pair, nil
 }
 
-// ClientInsightsQuery returns a new Insights query client
-func (c *Config) ClientInsightsQuery() (*insights.QueryClient, error) {
-	client := insights.NewQueryClient(c.InsightsQueryKey, c.InsightsAccountID)
+// proxy returns the function choosing the proxy each request is sent
+// through. The configured proxy and exclusions take precedence over those of
+// the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func (c *Config) proxy() (func(*http.Request) (*url.URL, error), error) {
+	proxyCfg := httpproxy.FromEnvironment()
 
-	if c.InsightsQueryURL != "" {
-		insightsURL, err := url.Parse(c.InsightsQueryURL)
+	if c.ProxyURL != "" {
+		// Parse errors quote the URL, along with any credentials it holds.
+		u, err := url.Parse(c.ProxyURL)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing Insights URL: %q", err)
+			return nil, fmt.Errorf("invalid proxy_url: not a valid URL")
 		}
-		insightsURL.Path = fmt.Sprintf("%s/%s/query", insightsURL.Path, c.InsightsAccountID)
-		client.URL = insightsURL
+
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxy_url: scheme must be http, https or socks5")
+		}
+
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy_url: host must not be empty")
+		}
+
+		proxyCfg.HTTPProxy = c.ProxyURL
+		proxyCfg.HTTPSProxy = c.ProxyURL
+	}
+
+	if c.NoProxy != "" {
+		proxyCfg.NoProxy = c.NoProxy
+	}
+
+	proxyFunc := proxyCfg.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+// contextTransport sends every request with the context of the operation the
+// client was made for, since the client itself does not take one.
+type contextTransport struct {
+	transport http.RoundTripper
+	ctx       context.Context
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	if len(c.InsightsQueryKey) > 1 {
-		if err := client.Validate(); err != nil {
+	return t.transport.RoundTrip(req.WithContext(t.ctx))
+}
+
+// clientTransport adapts a RoundTripper for use by newrelic-client-go, which
+// silently ignores any transport that is not an *http.Transport. The returned
+// transport hands every request to rt through a registered protocol.
+func clientTransport(rt http.RoundTripper) *http.Transport {
+	if t, ok := rt.(*http.Transport); ok {
+		return t
+	}
+
+	// A non-nil TLSNextProto keeps HTTP/2 from claiming the "https" protocol.
+	t := &http.Transport{
+		TLSNextProto: map[string]func(string, *tls.Conn) http.RoundTripper{},
+	}
+	t.RegisterProtocol("http", rt)
+	t.RegisterProtocol("https", rt)
+
+	return t
+}
+
+// retryTransport retries idempotent requests that fail with a rate limit, a
+// transient server error or a network error, waiting between attempts with a
+// jittered exponential backoff or for as long as the Retry-After header asks.
+type retryTransport struct {
+	transport  http.RoundTripper
+	maxRetries int
+	minWait    time.Duration
+	maxWait    time.Duration
+	timeout    time.Duration
+}
+
+func newRetryTransport(t http.RoundTripper, maxRetries int, maxWait time.Duration) *retryTransport {
+	if maxWait <= 0 {
+		maxWait = defaultRetryMaxWait
+	}
+
+	return &retryTransport{
+		transport:  t,
+		maxRetries: maxRetries,
+		minWait:    retryMinWait,
+		maxWait:    maxWait,
+		timeout:    requestTimeout,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
 			return nil, err
 		}
+		req.Body.Close()
 	}
 
-	log.Printf("[INFO] New Relic Insights query client configured")
+	retryable := isIdempotentRequest(req, body)
 
-	return client, nil
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+
+		r := req.Clone(ctx)
+		if body != nil {
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := t.transport.RoundTrip(r)
+
+		wait, ok := time.Duration(0), false
+		if retryable && attempt < t.maxRetries && shouldRetry(resp, err) {
+			wait, ok = t.retryWait(resp, attempt)
+		}
+
+		if !ok {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+
+			// The attempt's timeout also covers reading the response body.
+			resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+
+			return resp, nil
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		cancel()
+
+		log.Printf("[DEBUG] retrying %s %s in %s (attempt %d of %d)", req.Method, req.URL, wait, attempt+1, t.maxRetries)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// retryWait returns how long to wait before the next attempt. The wait asked
+// for by a Retry-After header is used as is, but the request is not retried
+// when it is longer than the maximum wait.
+func (t *retryTransport) retryWait(resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return wait, wait <= t.maxWait
+		}
+	}
+
+	wait := t.minWait << uint(attempt)
+	if wait <= 0 || wait > t.maxWait {
+		wait = t.maxWait
+	}
+
+	// Waits are spread between half and the full backoff, so that concurrent
+	// requests hitting the same limit do not retry in lockstep.
+	jitter := time.Duration(rand.Int63n(int64(wait/2) + 1))
+
+	return wait/2 + jitter, true
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as a date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(v); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+
+		return wait, true
+	}
+
+	return 0, false
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented)
+}
+
+// isIdempotentRequest reports whether a request can safely be sent more than
+// once. NerdGraph requests are always POSTs, so queries are told apart from
+// mutations by the GraphQL document in the body.
+func isIdempotentRequest(req *http.Request, body []byte) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return isNerdGraphQuery(body)
+	}
+
+	return false
+}
+
+// isNerdGraphQuery reports whether a request body is a GraphQL document that
+// only reads data.
+func isNerdGraphQuery(body []byte) bool {
+	var graphQL struct {
+		Query string `json:"query"`
+	}
+
+	if err := json.Unmarshal(body, &graphQL); err != nil || graphQL.Query == "" {
+		return false
+	}
+
+	query := strings.TrimSpace(graphQL.Query)
+
+	return !strings.HasPrefix(query, "mutation") && !strings.HasPrefix(query, "subscription")
+}
+
+// ClientInsightsInsert returns a new client for inserting Insights events,
+// whose requests are abandoned once ctx is done.
+func (c *Config) ClientInsightsInsert(ctx context.Context) (*InsightsInsertClient, error) {
+	if c.InsightsAccountID == "" || c.InsightsInsertKey == "" {
+		return nil, fmt.Errorf("insights_account_id and insights_insert_key must be set to insert Insights events")
+	}
+
+	insertURL, err := insightsURL(c.InsightsInsertURL, "insights_insert_url", c.InsightsAccountID, "events")
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := c.insightsHTTPClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InsightsInsertClient{
+		url:        insertURL,
+		insertKey:  c.InsightsInsertKey,
+		userAgent:  c.userAgent,
+		httpClient: httpClient,
+	}, nil
+}
+
+// ClientInsightsQuery returns a new client for querying Insights events,
+// whose requests are abandoned once ctx is done.
+func (c *Config) ClientInsightsQuery(ctx context.Context) (*InsightsQueryClient, error) {
+	if c.InsightsAccountID == "" || c.InsightsQueryKey == "" {
+		return nil, fmt.Errorf("insights_account_id and insights_query_key must be set to query Insights events")
+	}
+
+	queryURL, err := insightsURL(c.InsightsQueryURL, "insights_query_url", c.InsightsAccountID, "query")
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := c.insightsHTTPClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InsightsQueryClient{
+		url:        queryURL,
+		queryKey:   c.InsightsQueryKey,
+		userAgent:  c.userAgent,
+		httpClient: httpClient,
+	}, nil
 }
 
 // ProviderConfig for the custom provider
 type ProviderConfig struct {
-	NewClient            *nr.NewRelic
-	InsightsInsertClient *insights.InsertClient
-	InsightsQueryClient  *insights.QueryClient
-	AccountID            int
-	PersonalAPIKey       string
+	NewClient      *nr.NewRelic
+	AccountID      int
+	PersonalAPIKey string
+	RateLimiter    *RateLimiter
+
+	// ReadOnly makes every resource refuse to create, update or delete.
+	ReadOnly bool
+
+	clientConfig *Config
+	stopCtx      context.Context
+	auditEvents  *auditEvents
+	listCache    *listCache
+
+	// defaultTags are added to the tags of every resource whose object is
+	// an entity.
+	defaultTags map[string]string
+
+	// locks serializes changes to the same object made by different
+	// resources, such as the channels of an alert policy.
+	locks *mutexkv.MutexKV
+
+	// instrumentation records a transaction for each resource operation.
+	instrumentation *instrumentation
+}
+
+// clientWithTimeout returns a client whose requests are abandoned once the
+// timeout passes or Terraform is interrupted, along with the function that
+// releases the timeout once the client is no longer needed.
+func (c *ProviderConfig) clientWithTimeout(timeout time.Duration) (*nr.NewRelic, context.CancelFunc, error) {
+	if c.clientConfig == nil {
+		return c.NewClient, func() {}, nil
+	}
+
+	ctx, cancel := c.contextWithTimeout(timeout)
+
+	client, err := c.clientConfig.ClientWithContext(ctx)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	return client, cancel, nil
+}
+
+// insightsInsertClient returns a client for inserting Insights events whose
+// requests are abandoned once the timeout passes or Terraform is interrupted.
+// Insights clients are only made when a resource needs one, so that Insights
+// settings do not matter to configurations that never use Insights.
+func (c *ProviderConfig) insightsInsertClient(timeout time.Duration) (*InsightsInsertClient, context.CancelFunc, error) {
+	if c.clientConfig == nil {
+		return nil, nil, fmt.Errorf("the provider is not configured for Insights")
+	}
+
+	ctx, cancel := c.contextWithTimeout(timeout)
+
+	client, err := c.clientConfig.ClientInsightsInsert(ctx)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	return client, cancel, nil
+}
+
+// insightsQueryClient returns a client for querying Insights events whose
+// requests are abandoned once the timeout passes or Terraform is interrupted.
+func (c *ProviderConfig) insightsQueryClient(timeout time.Duration) (*InsightsQueryClient, context.CancelFunc, error) {
+	if c.clientConfig == nil {
+		return nil, nil, fmt.Errorf("the provider is not configured for Insights")
+	}
+
+	ctx, cancel := c.contextWithTimeout(timeout)
+
+	client, err := c.clientConfig.ClientInsightsQuery(ctx)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	return client, cancel, nil
+}
+
+// lockAlertPolicy waits until no other resource is changing the alert policy
+// or its channels, returning the function that lets them change it again.
+// Changes to different policies are still made in parallel.
+func (c *ProviderConfig) lockAlertPolicy(policyID int) func() {
+	if c.locks == nil {
+		return func() {}
+	}
+
+	key := fmt.Sprintf("alert_policy/%d", policyID)
+	c.locks.Lock(key)
+
+	return func() { c.locks.Unlock(key) }
+}
+
+// contextWithTimeout returns a context that is done once the timeout passes or
+// Terraform is interrupted.
+func (c *ProviderConfig) contextWithTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+	stopCtx := c.stopCtx
+	if stopCtx == nil {
+		stopCtx = context.Background()
+	}
+
+	return context.WithTimeout(stopCtx, timeout)
 }
 
 func (c *ProviderConfig) hasNerdGraphCredentials() bool {
 	return c.AccountID > 0 && c.PersonalAPIKey != ""
 }
+
+// validateCredentials makes a cheap call to each API the configured keys are
+// for, so that a key or account ID that does not work is reported by name
+// before any resource is touched.
+func (c *ProviderConfig) validateCredentials(adminAPIKey string) error {
+	client := c.NewClient
+
+	if adminAPIKey != "" {
+		// The dashboards API only accepts the admin API key, and the
+		// title filter keeps the response to a single short page.
+		_, err := client.Dashboards.ListDashboards(&dashboards.ListDashboardsParams{Title: serviceName})
+		if err != nil {
+			return fmt.Errorf("api_key could not be validated against the REST API: %w", err)
+		}
+	}
+
+	if c.PersonalAPIKey == "" {
+		if c.AccountID > 0 {
+			return fmt.Errorf("account_id %d could not be validated: personal_api_key is required to access NerdGraph", c.AccountID)
+		}
+
+		return nil
+	}
+
+	if c.AccountID == 0 {
+		if _, err := client.NerdGraph.Query(`{ actor { user { id } } }`, nil); err != nil {
+			return fmt.Errorf("personal_api_key could not be validated against NerdGraph: %w", err)
+		}
+
+		return nil
+	}
+
+	query := `query($accountId: Int!) { actor { account(id: $accountId) { id name } } }`
+	resp, err := client.NerdGraph.Query(query, map[string]interface{}{"accountId": c.AccountID})
+	if err != nil {
+		if unsuccessfulResponse(err) {
+			return fmt.Errorf("personal_api_key could not be validated against NerdGraph: %w", err)
+		}
+
+		return fmt.Errorf("account_id %d is not accessible with the configured personal_api_key: %w", c.AccountID, err)
+	}
+
+	if !nerdGraphAccountFound(resp) {
+		return fmt.Errorf("account_id %d is not accessible with the configured personal_api_key", c.AccountID)
+	}
+
+	return nil
+}
+
+func nerdGraphAccountFound(resp interface{}) bool {
+	queryResp, ok := resp.(nerdgraph.QueryResponse)
+	if !ok {
+		return false
+	}
+
+	actor, _ := queryResp.Actor.(map[string]interface{})
+	account, _ := actor["account"].(map[string]interface{})
+
+	return account != nil && account["id"] != nil
+}
@@ -141,11 +141,11 @@ func dataSourceNewRelicAlertChannel() *schema.Resource {
 }
 
 func dataSourceNewRelicAlertChannelRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	providerConfig := meta.(*ProviderConfig)
 
 	log.Printf("[INFO] Reading New Relic Alert Channels")
 
-	channels, err := client.Alerts.ListChannels()
+	channels, err := providerConfig.listAlertChannels(providerConfig.NewClient)
 	if err != nil {
 		return err
 	}
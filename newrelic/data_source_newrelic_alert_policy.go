@@ -58,9 +58,7 @@ func dataSourceNewRelicAlertPolicyRead(d *schema.ResourceData, meta interface{})
 	name := d.Get("name").(string)
 	accountID := d.Get("account_id").(int)
 
-	params := alerts.AlertsPoliciesSearchCriteriaInput{}
-
-	policies, err := client.Alerts.QueryPolicySearch(accountID, params)
+	policies, err := cfg.searchAlertPolicies(client, accountID)
 	if err != nil {
 		return err
 	}
@@ -35,16 +35,13 @@ func dataSourceNewRelicApplication() *schema.Resource {
 }
 
 func dataSourceNewRelicApplicationRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	providerConfig := meta.(*ProviderConfig)
 
 	log.Printf("[INFO] Reading New Relic applications")
 
 	name := d.Get("name").(string)
-	params := apm.ListApplicationsParams{
-		Name: name,
-	}
 
-	applications, err := client.APM.ListApplications(&params)
+	applications, err := providerConfig.listApplications(providerConfig.NewClient, name)
 	if err != nil {
 		return err
 	}
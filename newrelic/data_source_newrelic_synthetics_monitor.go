@@ -28,13 +28,12 @@ func dataSourceNewRelicSyntheticsMonitor() *schema.Resource {
 }
 
 func dataSourceNewRelicSyntheticsMonitorRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	providerConfig := meta.(*ProviderConfig)
 
 	log.Printf("[INFO] Reading New Relic synthetics monitors")
 
 	name := d.Get("name").(string)
-	monitors, err := client.Synthetics.ListMonitors()
-
+	monitors, err := providerConfig.listSyntheticsMonitors(providerConfig.NewClient)
 	if err != nil {
 		return err
 	}
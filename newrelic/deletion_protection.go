@@ -0,0 +1,68 @@
+package newrelic
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// deletionProtectionSchema returns the `deletion_protection` attribute of the
+// resources that can be protected from being deleted.
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Whether the resource is protected from being deleted, including when it needs to be replaced. Must be set to false and applied before the resource can be deleted.",
+	}
+}
+
+// Wraps the functions of a resource with a `deletion_protection` attribute, so
+// that it is not deleted while the attribute is true in its state. The
+// attribute is only known to Terraform, so changing it alone makes no API
+// call, and imported resources start unprotected.
+func protectFromDeletion(name string, r *schema.Resource) {
+	if _, ok := r.Schema["deletion_protection"]; !ok {
+		return
+	}
+
+	if del := r.Delete; del != nil {
+		r.Delete = func(d *schema.ResourceData, meta interface{}) error {
+			if d.Get("deletion_protection").(bool) {
+				return fmt.Errorf("cannot delete %s %s: deletion_protection is enabled, set it to false and apply before deleting", name, d.Id())
+			}
+
+			return del(d, meta)
+		}
+	}
+
+	if update := r.Update; update != nil {
+		r.Update = func(d *schema.ResourceData, meta interface{}) error {
+			for k := range r.Schema {
+				if k != "deletion_protection" && d.HasChange(k) {
+					return update(d, meta)
+				}
+			}
+
+			return nil
+		}
+	}
+
+	if r.Importer != nil && r.Importer.State != nil {
+		importState := r.Importer.State
+		r.Importer.State = func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			imported, err := importState(d, meta)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, i := range imported {
+				if err := i.Set("deletion_protection", false); err != nil {
+					return nil, err
+				}
+			}
+
+			return imported, nil
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package newrelic
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtectFromDeletion(t *testing.T) {
+	var updates, deletes int
+
+	r := &schema.Resource{
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			updates++
+			return nil
+		},
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			deletes++
+			return nil
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"name":                {Type: schema.TypeString, Required: true},
+			"deletion_protection": deletionProtectionSchema(),
+		},
+	}
+	protectFromDeletion("newrelic_test", r)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":                "checkout",
+		"deletion_protection": true,
+	})
+	d.SetId("42")
+
+	require.EqualError(t, r.Delete(d, nil), "cannot delete newrelic_test 42: deletion_protection is enabled, set it to false and apply before deleting")
+	require.Equal(t, 0, deletes)
+
+	require.NoError(t, d.Set("deletion_protection", false))
+	require.NoError(t, r.Delete(d, nil))
+	require.Equal(t, 1, deletes)
+
+	// Changing only the protection is not sent to the API.
+	update := func(config map[string]interface{}) {
+		state := &terraform.InstanceState{
+			ID:         "42",
+			Attributes: map[string]string{"id": "42", "name": "checkout", "deletion_protection": "false"},
+		}
+
+		diff, err := r.Diff(state, terraform.NewResourceConfigRaw(config), nil)
+		require.NoError(t, err)
+
+		data, err := schema.InternalMap(r.Schema).Data(state, diff)
+		require.NoError(t, err)
+		require.NoError(t, r.Update(data, nil))
+	}
+
+	update(map[string]interface{}{"name": "checkout", "deletion_protection": true})
+	require.Equal(t, 0, updates)
+
+	update(map[string]interface{}{"name": "payments", "deletion_protection": true})
+	require.Equal(t, 1, updates)
+
+	imported, err := r.Importer.State(r.Data(nil), nil)
+	require.NoError(t, err)
+	require.Equal(t, false, imported[0].Get("deletion_protection"))
+}
@@ -0,0 +1,296 @@
+package newrelic
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/newrelic/newrelic-client-go/pkg/entities"
+
+	"github.com/terraform-providers/terraform-provider-newrelic/internal/compoundid"
+)
+
+// entityGUIDFunc returns the GUID of the New Relic One entity of a resource.
+type entityGUIDFunc func(d *schema.ResourceData, accountID int) (string, error)
+
+// entityGUIDs lists the resources whose objects are New Relic One entities,
+// which can be tagged.
+var entityGUIDs = map[string]entityGUIDFunc{
+	"newrelic_application_settings": accountEntityGUID(applicationID, "application_id", "APM", "APPLICATION"),
+	"newrelic_dashboard":            accountEntityGUID(dashboardID, "dashboard_id", "VIZ", "DASHBOARD"),
+	"newrelic_synthetics_monitor":   accountEntityGUID(syntheticsMonitorID, "monitor_id", "SYNTH", "MONITOR"),
+	"newrelic_workload": func(d *schema.ResourceData, accountID int) (string, error) {
+		ids, err := workloadID.Parse(d.Id())
+		if err != nil {
+			return "", err
+		}
+
+		return ids.Value("guid"), nil
+	},
+}
+
+// accountEntityGUID returns the GUID of entities built from the account ID of
+// the provider and the ID of the resource, which is how New Relic One
+// identifies the objects of the older APIs.
+func accountEntityGUID(ids *compoundid.Schema, part string, domain string, entityType string) entityGUIDFunc {
+	return func(d *schema.ResourceData, accountID int) (string, error) {
+		if accountID == 0 {
+			return "", fmt.Errorf("account_id must be set to manage the tags of %s entities", strings.ToLower(entityType))
+		}
+
+		id, err := ids.Parse(d.Id())
+		if err != nil {
+			return "", err
+		}
+
+		guid := fmt.Sprintf("%d|%s|%s|%s", accountID, domain, entityType, id.Value(part))
+
+		return base64.RawStdEncoding.EncodeToString([]byte(guid)), nil
+	}
+}
+
+// entityTagsSchema returns the `tags` attribute of the resources whose
+// objects can be tagged.
+func entityTagsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Optional:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The tags of the entity, merged with the default_tags of the provider.",
+	}
+}
+
+// entityTagsAllSchema returns the `tags_all` attribute, which holds the tags
+// of the entity that are managed by Terraform.
+func entityTagsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The tags of the entity managed by Terraform, including the default_tags of the provider.",
+	}
+}
+
+// Wraps the functions of a resource with `tags` and `tags_all` attributes so
+// that the tags of its entity are managed along with it.
+//
+// The tags of the resource are merged with the default tags of the provider
+// into `tags_all`, which is what is written to the entity. Only those keys
+// are read back, so tags added to the entity by other means, such as label
+// resources, are not reported as drift, and neither are the default tags in
+// `tags`. Changing only the tags makes no call to the API of the object.
+func manageEntityTags(name string, r *schema.Resource) {
+	guid, ok := entityGUIDs[name]
+	if _, hasTags := r.Schema["tags_all"]; !ok || !hasTags {
+		return
+	}
+
+	customizeDiff := r.CustomizeDiff
+	r.CustomizeDiff = func(d *schema.ResourceDiff, meta interface{}) error {
+		if customizeDiff != nil {
+			if err := customizeDiff(d, meta); err != nil {
+				return err
+			}
+		}
+
+		if !d.NewValueKnown("tags") {
+			return d.SetNewComputed("tags_all")
+		}
+
+		tagsAll := mergeEntityTags(meta, d.Get("tags"))
+		if old, _ := d.GetChange("tags_all"); !reflect.DeepEqual(expandEntityTags(old), tagsAll) {
+			return d.SetNew("tags_all", tagsAll)
+		}
+
+		return nil
+	}
+
+	if create := r.Create; create != nil {
+		r.Create = func(d *schema.ResourceData, meta interface{}) error {
+			if err := create(d, meta); err != nil {
+				return err
+			}
+
+			return updateEntityTags(d, meta, guid, schema.TimeoutCreate)
+		}
+	}
+
+	if read := r.Read; read != nil {
+		r.Read = func(d *schema.ResourceData, meta interface{}) error {
+			if err := read(d, meta); err != nil || d.Id() == "" {
+				return err
+			}
+
+			return readEntityTags(d, meta, guid)
+		}
+	}
+
+	if update := r.Update; update != nil {
+		r.Update = func(d *schema.ResourceData, meta interface{}) error {
+			for k := range r.Schema {
+				if k != "tags" && k != "tags_all" && d.HasChange(k) {
+					if err := update(d, meta); err != nil {
+						return err
+					}
+
+					break
+				}
+			}
+
+			return updateEntityTags(d, meta, guid, schema.TimeoutUpdate)
+		}
+	}
+}
+
+// mergeEntityTags returns the default tags of the provider overridden by the
+// given tags.
+func mergeEntityTags(meta interface{}, tags interface{}) map[string]string {
+	merged := map[string]string{}
+
+	if providerConfig, ok := meta.(*ProviderConfig); ok {
+		for k, v := range providerConfig.defaultTags {
+			merged[k] = v
+		}
+	}
+
+	for k, v := range expandEntityTags(tags) {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+func expandEntityTags(tags interface{}) map[string]string {
+	expanded := map[string]string{}
+
+	m, _ := tags.(map[string]interface{})
+	for k, v := range m {
+		expanded[k] = v.(string)
+	}
+
+	return expanded
+}
+
+// updateEntityTags writes the changes between the tags in the state and the
+// merged tags of the resource to its entity. Keys that are removed or given
+// another value are deleted first, as adding a tag only adds a value to its
+// key.
+func updateEntityTags(d *schema.ResourceData, meta interface{}, guid entityGUIDFunc, timeoutKey string) error {
+	old, _ := d.GetChange("tags_all")
+	oldTags := expandEntityTags(old)
+	newTags := mergeEntityTags(meta, d.Get("tags"))
+
+	deleted := []string{}
+	added := []entities.Tag{}
+
+	for k, v := range oldTags {
+		if nv, ok := newTags[k]; !ok || nv != v {
+			deleted = append(deleted, k)
+		}
+	}
+
+	for k, v := range newTags {
+		if ov, ok := oldTags[k]; !ok || ov != v {
+			added = append(added, entities.Tag{Key: k, Values: []string{v}})
+		}
+	}
+
+	if len(deleted) > 0 || len(added) > 0 {
+		sort.Strings(deleted)
+		sort.Slice(added, func(i, j int) bool { return added[i].Key < added[j].Key })
+
+		entityGUID, err := guid(d, meta.(*ProviderConfig).AccountID)
+		if err != nil {
+			return err
+		}
+
+		client, cancel, err := resourceClient(d, meta, timeoutKey)
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		if len(deleted) > 0 {
+			if err := client.Entities.DeleteTags(entityGUID, deleted); err != nil {
+				return fmt.Errorf("error deleting tags %s: %w", strings.Join(deleted, ", "), err)
+			}
+		}
+
+		if len(added) > 0 {
+			if err := client.Entities.AddTags(entityGUID, added); err != nil {
+				return fmt.Errorf("error adding tags: %w", err)
+			}
+		}
+	}
+
+	return d.Set("tags_all", newTags)
+}
+
+// readEntityTags sets the tags of the entity managed by Terraform. A key that
+// also has values added by other means keeps the value it was given, as long
+// as it is still one of them.
+func readEntityTags(d *schema.ResourceData, meta interface{}, guid entityGUIDFunc) error {
+	tags := expandEntityTags(d.Get("tags"))
+	tagsAll := mergeEntityTags(meta, d.Get("tags"))
+	for k, v := range expandEntityTags(d.Get("tags_all")) {
+		if _, ok := tagsAll[k]; !ok {
+			tagsAll[k] = v
+		}
+	}
+
+	if len(tagsAll) == 0 {
+		return nil
+	}
+
+	entityGUID, err := guid(d, meta.(*ProviderConfig).AccountID)
+	if err != nil {
+		return err
+	}
+
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	entityTags, err := client.Entities.ListTags(entityGUID)
+	if err != nil {
+		return err
+	}
+
+	values := map[string][]string{}
+	for _, t := range entityTags {
+		values[t.Key] = t.Values
+	}
+
+	if err := d.Set("tags", flattenEntityTags(tags, values)); err != nil {
+		return err
+	}
+
+	return d.Set("tags_all", flattenEntityTags(tagsAll, values))
+}
+
+// flattenEntityTags returns the value of each of the given tags found on the
+// entity.
+func flattenEntityTags(tags map[string]string, values map[string][]string) map[string]string {
+	flattened := map[string]string{}
+
+	for k, v := range tags {
+		found, ok := values[k]
+		if !ok {
+			continue
+		}
+
+		flattened[k] = strings.Join(found, ",")
+		for _, value := range found {
+			if value == v {
+				flattened[k] = v
+			}
+		}
+	}
+
+	return flattened
+}
@@ -0,0 +1,131 @@
+package newrelic
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountEntityGUID(t *testing.T) {
+	r := resourceNewRelicApplicationSettings()
+	d := r.Data(nil)
+	d.SetId("215037795")
+
+	guid := entityGUIDs["newrelic_application_settings"]
+
+	found, err := guid(d, 2520528)
+	require.NoError(t, err)
+	require.Equal(t, "MjUyMDUyOHxBUE18QVBQTElDQVRJT058MjE1MDM3Nzk1", found)
+
+	_, err = guid(d, 0)
+	require.EqualError(t, err, "account_id must be set to manage the tags of application entities")
+}
+
+func TestMergeEntityTags(t *testing.T) {
+	meta := &ProviderConfig{defaultTags: map[string]string{"team": "platform", "managed-by": "terraform"}}
+
+	merged := mergeEntityTags(meta, map[string]interface{}{"team": "checkout"})
+	require.Equal(t, map[string]string{"team": "checkout", "managed-by": "terraform"}, merged)
+
+	require.Equal(t, map[string]string{}, mergeEntityTags(&ProviderConfig{}, nil))
+}
+
+func TestFlattenEntityTags(t *testing.T) {
+	values := map[string][]string{
+		"team":  {"checkout", "payments"},
+		"env":   {"staging"},
+		"owner": {"sre"},
+	}
+
+	flattened := flattenEntityTags(map[string]string{
+		"team":   "checkout",
+		"env":    "production",
+		"region": "us",
+	}, values)
+
+	// Values added by other means are kept, tags removed from the entity are
+	// dropped and tags that are not managed are left out.
+	require.Equal(t, map[string]string{"team": "checkout", "env": "staging"}, flattened)
+}
+
+func TestManageEntityTags(t *testing.T) {
+	srv := newMockAPIServer()
+	defer srv.Close()
+
+	cfg := srv.config()
+	client, err := cfg.Client()
+	require.NoError(t, err)
+
+	meta := &ProviderConfig{
+		NewClient:   client,
+		AccountID:   mockAccountID,
+		defaultTags: map[string]string{"managed-by": "terraform"},
+	}
+
+	var updates int
+
+	r := &schema.Resource{
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return nil
+		},
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			updates++
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"name":     {Type: schema.TypeString, Required: true},
+			"tags":     entityTagsSchema(),
+			"tags_all": entityTagsAllSchema(),
+		},
+	}
+	manageEntityTags("newrelic_application_settings", r)
+
+	state := &terraform.InstanceState{
+		ID: "42",
+		Attributes: map[string]string{
+			"id":                  "42",
+			"name":                "checkout",
+			"tags.%":              "0",
+			"tags_all.%":          "1",
+			"tags_all.managed-by": "terraform",
+		},
+	}
+
+	plan := func(config map[string]interface{}) *schema.ResourceData {
+		diff, err := r.Diff(state, terraform.NewResourceConfigRaw(config), meta)
+		require.NoError(t, err)
+
+		data, err := schema.InternalMap(r.Schema).Data(state, diff)
+		require.NoError(t, err)
+
+		return data
+	}
+
+	// Tags that are already applied make no changes.
+	diff, err := r.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{"name": "checkout"}), meta)
+	require.NoError(t, err)
+	require.True(t, diff.Empty())
+
+	// Changing only the tags is not sent to the API of the object.
+	data := plan(map[string]interface{}{"name": "checkout", "tags": map[string]interface{}{"managed-by": "platform-team", "team": "checkout"}})
+	require.NoError(t, r.Update(data, meta))
+	require.Equal(t, 0, updates)
+	require.Equal(t, map[string]interface{}{"managed-by": "platform-team", "team": "checkout"}, data.Get("tags_all"))
+
+	guid := "MjUyMDUyOHxBUE18QVBQTElDQVRJT058NDI"
+	require.Equal(t, map[string][]string{"managed-by": {"platform-team"}, "team": {"checkout"}}, srv.entityTags[guid])
+
+	// Tags added by other means are left alone.
+	srv.entityTags[guid]["owner"] = []string{"sre"}
+	state = data.State()
+
+	require.NoError(t, r.Read(data, meta))
+	require.Equal(t, map[string]interface{}{"managed-by": "platform-team", "team": "checkout"}, data.Get("tags"))
+
+	data = plan(map[string]interface{}{"name": "payments"})
+	require.NoError(t, r.Update(data, meta))
+	require.Equal(t, 1, updates)
+	require.Equal(t, map[string][]string{"managed-by": {"terraform"}, "owner": {"sre"}}, srv.entityTags[guid])
+}
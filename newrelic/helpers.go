@@ -2,7 +2,6 @@ package newrelic
 
 import (
 	"encoding/json"
-	"fmt"
 	"os"
 	"sort"
 	"strconv"
@@ -10,54 +9,6 @@ import (
 	"unicode"
 )
 
-func parseIDs(serializedID string, count int) ([]int, error) {
-	rawIDs := strings.SplitN(serializedID, ":", count)
-	if len(rawIDs) != count {
-		return []int{}, fmt.Errorf("unable to parse ID %v", serializedID)
-	}
-
-	ids := make([]int, count)
-
-	for i, rawID := range rawIDs {
-		id, err := strconv.ParseInt(rawID, 10, 32)
-		if err != nil {
-			return ids, err
-		}
-
-		ids[i] = int(id)
-	}
-
-	return ids, nil
-}
-
-// Converts a hash of IDs into an array.
-// Examples: "12345:54432:66564" -> []int{12345,54432,66564}
-func parseHashedIDs(serializedID string) ([]int, error) {
-	rawIDs := strings.Split(serializedID, ":")
-	ids := make([]int, len(rawIDs))
-
-	for i, rawID := range rawIDs {
-		id, err := strconv.ParseInt(rawID, 10, 32)
-		if err != nil {
-			return ids, err
-		}
-
-		ids[i] = int(id)
-	}
-
-	return ids, nil
-}
-
-func serializeIDs(ids []int) string {
-	idStrings := make([]string, len(ids))
-
-	for i, id := range ids {
-		idStrings[i] = strconv.Itoa(id)
-	}
-
-	return strings.Join(idStrings, ":")
-}
-
 // Helper for converting data to pretty JSON
 // nolint:deadcode,unused
 func toJSON(data interface{}) string {
@@ -15,44 +15,6 @@ var (
 	nrInternalAccount = os.Getenv("NR_ACC_TESTING") != ""
 )
 
-func TestParseIDs_Basic(t *testing.T) {
-	ids, err := parseIDs("1:2", 2)
-
-	require.NoError(t, err)
-	require.Equal(t, 2, len(ids))
-	require.Equal(t, 1, ids[0])
-	require.Equal(t, 2, ids[1])
-}
-
-func TestParseIDs_BadIDs(t *testing.T) {
-	_, err := parseIDs("12", 2)
-	require.Error(t, err)
-
-	_, err = parseIDs("a:b", 2)
-	require.Error(t, err)
-}
-
-func TestParseHashedIDs_Basic(t *testing.T) {
-	expected := []int{1, 2, 3}
-	result, err := parseHashedIDs("1:2:3")
-
-	require.NoError(t, err)
-	require.Equal(t, 3, len(result))
-	require.Equal(t, expected, result)
-}
-
-func TestParseHashedIDs_Invalid(t *testing.T) {
-	_, err := parseHashedIDs("123:abc")
-
-	require.Error(t, err)
-}
-
-func TestSerializeIDs_Basic(t *testing.T) {
-	id := serializeIDs([]int{1, 2})
-
-	require.Equal(t, "1:2", id)
-}
-
 func TestStripWhitespace(t *testing.T) {
 	json := " { \"key\": \"value\" } "
 	e := "{\"key\":\"value\"}"
@@ -87,6 +49,27 @@ func testAccDeleteNewRelicAlertPolicy(name string) func() {
 	}
 }
 
+// testAccCreateNewRelicAlertPolicyREST creates an alert policy through the
+// REST API, which needs no personal API key, returning its ID.
+func testAccCreateNewRelicAlertPolicyREST(t *testing.T, name string) int {
+	cfg := Config{
+		AdminAPIKey: os.Getenv("NEWRELIC_API_KEY"),
+		APIURL:      os.Getenv("NEWRELIC_API_URL"),
+		userAgent:   "terraform-provider-newrelic-test",
+	}
+
+	client, err := cfg.Client()
+	require.NoError(t, err)
+
+	policy, err := client.Alerts.CreatePolicy(alerts.Policy{
+		Name:               name,
+		IncidentPreference: alerts.IncidentPreferenceTypes.PerPolicy,
+	})
+	require.NoError(t, err)
+
+	return policy.ID
+}
+
 // A custom check function to log the internal state during a test run.
 // nolint:deadcode,unused
 func logState(t *testing.T) resource.TestCheckFunc {
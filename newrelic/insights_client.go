@@ -0,0 +1,149 @@
+package newrelic
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	insights "github.com/newrelic/go-insights/client"
+	"github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/region"
+)
+
+// InsightsInsertClient posts events to the Insights insert API. Unlike the
+// clients of go-insights, which always use Go's default HTTP settings, it
+// sends requests through the transport shared by the provider's other
+// clients.
+type InsightsInsertClient struct {
+	url        string
+	insertKey  string
+	userAgent  string
+	httpClient *http.Client
+}
+
+// PostEvent posts an event, or a slice of events, to Insights. Events are
+// compressed with gzip, as go-insights does.
+func (c *InsightsInsertClient) PostEvent(data interface{}) error {
+	var body bytes.Buffer
+
+	w := gzip.NewWriter(&body)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		return fmt.Errorf("error marshaling event data: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error compressing event data: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.url, &body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("X-Insert-Key", c.insertKey)
+
+	var resp struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error"`
+	}
+
+	if err := insightsDo(c.httpClient, req, c.userAgent, &resp); err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("events were not accepted by Insights: %s", resp.Error)
+	}
+
+	return nil
+}
+
+// InsightsQueryClient runs NRQL queries with the Insights query API, through
+// the transport shared by the provider's other clients.
+type InsightsQueryClient struct {
+	url        string
+	queryKey   string
+	userAgent  string
+	httpClient *http.Client
+}
+
+// QueryEvents runs a NRQL query.
+func (c *InsightsQueryClient) QueryEvents(nrql string) (*insights.QueryResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, c.url+"?"+url.Values{"nrql": {nrql}}.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Query-Key", c.queryKey)
+
+	resp := &insights.QueryResponse{}
+	if err := insightsDo(c.httpClient, req, c.userAgent, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// insightsDo sends a request to an Insights API, decoding the JSON response
+// into v.
+func insightsDo(client *http.Client, req *http.Request, userAgent string, v interface{}) error {
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading Insights response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.NewUnexpectedStatusCode(resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error decoding Insights response: %w", err)
+	}
+
+	return nil
+}
+
+// insightsURL returns the URL of an Insights API for the given account. The
+// base URL is that of the default region when it is not set, keyed by the
+// provider attribute that sets it.
+func insightsURL(baseURL string, attribute string, accountID string, path string) (string, error) {
+	if baseURL == "" {
+		baseURL = regionEndpoints[region.Default][attribute]
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing Insights URL: %w", err)
+	}
+
+	u.Path = fmt.Sprintf("%s/%s/%s", u.Path, accountID, path)
+
+	return u.String(), nil
+}
+
+// insightsHTTPClient returns an HTTP client sending requests through the
+// config's shared transport, with the given context.
+func (c *Config) insightsHTTPClient(ctx context.Context) (*http.Client, error) {
+	t, err := c.sharedTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Transport: &contextTransport{transport: t, ctx: ctx}}, nil
+}
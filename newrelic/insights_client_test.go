@@ -0,0 +1,91 @@
+package newrelic
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/region"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsightsClients(t *testing.T) {
+	var inserted []map[string]interface{}
+
+	// A TLS server can only be reached with the configured CA certificate,
+	// which go-insights' own clients would not trust.
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "terraform-provider-newrelic-test", r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/accounts/1/events":
+			require.Equal(t, "insert-key", r.Header.Get("X-Insert-Key"))
+			require.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+
+			body, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.NewDecoder(body).Decode(&inserted))
+			_, _ = w.Write([]byte(`{"success":true}`))
+		case "/v1/accounts/1/query":
+			require.Equal(t, "query-key", r.Header.Get("X-Query-Key"))
+			if r.URL.Query().Get("nrql") != "SELECT count(*) FROM TerraformChange" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"results":[{"count":1}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		CACertFile:        string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})),
+		InsightsAccountID: "1",
+		InsightsInsertKey: "insert-key",
+		InsightsInsertURL: srv.URL + "/v1/accounts",
+		InsightsQueryKey:  "query-key",
+		InsightsQueryURL:  srv.URL + "/v1/accounts",
+		userAgent:         "terraform-provider-newrelic-test",
+	}
+
+	insertClient, err := cfg.ClientInsightsInsert(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, insertClient.PostEvent([]map[string]interface{}{{"eventType": "TerraformChange"}}))
+	require.Equal(t, []map[string]interface{}{{"eventType": "TerraformChange"}}, inserted)
+
+	queryClient, err := cfg.ClientInsightsQuery(context.Background())
+	require.NoError(t, err)
+
+	resp, err := queryClient.QueryEvents("SELECT count(*) FROM TerraformChange")
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+
+	_, err = queryClient.QueryEvents("SELECT count(*) FROM Missing")
+	require.Error(t, err)
+}
+
+func TestInsightsURL_DefaultRegion(t *testing.T) {
+	u, err := insightsURL("", "insights_insert_url", "1", "events")
+	require.NoError(t, err)
+	require.Equal(t, regionEndpoints[region.US]["insights_insert_url"]+"/1/events", u)
+
+	u, err = insightsURL("", "insights_query_url", "1", "query")
+	require.NoError(t, err)
+	require.Equal(t, regionEndpoints[region.US]["insights_query_url"]+"/1/query", u)
+}
+
+func TestInsightsClients_NotConfigured(t *testing.T) {
+	cfg := &Config{InsightsAccountID: "1"}
+
+	_, err := cfg.ClientInsightsInsert(context.Background())
+	require.EqualError(t, err, "insights_account_id and insights_insert_key must be set to insert Insights events")
+
+	_, err = cfg.ClientInsightsQuery(context.Background())
+	require.EqualError(t, err, "insights_account_id and insights_query_key must be set to query Insights events")
+}
@@ -0,0 +1,161 @@
+package newrelic
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/logging"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	agent "github.com/newrelic/go-agent/v3/newrelic"
+)
+
+// The name the provider reports its own operations under, unless another is
+// configured.
+const defaultInstrumentationAppName = "terraform-provider-newrelic"
+
+// The time allowed for the data recorded by the provider to be sent when it
+// stops, within the 2 seconds Terraform gives plugins to exit.
+const instrumentationFlushTimeout = 1500 * time.Millisecond
+
+// The instrumentations of the providers configured in this process, whose
+// data is sent once Terraform is done with them.
+var instrumentations struct {
+	sync.Mutex
+	all []*instrumentation
+}
+
+// instrumentation reports the operations of the provider to New Relic with
+// the Go agent. One application records every operation of the provider, and
+// sends its data once a minute and once more when the provider stops.
+type instrumentation struct {
+	app  *agent.Application
+	once sync.Once
+}
+
+// expandInstrumentation returns the instrumentation configured by the
+// provider's `instrumentation` block, or nil when it is not enabled. Data is
+// sent to the collector with the TLS settings of the given config, so that a
+// local stand-in collector can be trusted with `cacert_file`. The agent
+// connects in the background, so configuring the provider never waits for
+// the collector, and operations run before it has connected are not recorded.
+func expandInstrumentation(cfg []interface{}, c *Config) (*instrumentation, error) {
+	if len(cfg) == 0 || cfg[0] == nil {
+		return nil, nil
+	}
+
+	block := cfg[0].(map[string]interface{})
+	if !block["enabled"].(bool) {
+		return nil, nil
+	}
+
+	transport, err := c.tlsTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []agent.ConfigOption{
+		agent.ConfigAppName(block["app_name"].(string)),
+		agent.ConfigLicense(block["license_key"].(string)),
+		// Trace headers are not meant for New Relic's own APIs.
+		agent.ConfigDistributedTracerEnabled(false),
+		func(cfg *agent.Config) {
+			cfg.Host = block["host"].(string)
+			cfg.Transport = transport
+		},
+	}
+
+	if logging.LogLevel() != "" {
+		options = append(options, agent.ConfigDebugLogger(log.Writer()))
+	}
+
+	app, err := agent.NewApplication(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &instrumentation{app: app}
+
+	instrumentations.Lock()
+	instrumentations.all = append(instrumentations.all, i)
+	instrumentations.Unlock()
+
+	return i, nil
+}
+
+// shutdown sends the data recorded so far and stops the agent. Only the first
+// call has any effect.
+func (i *instrumentation) shutdown() {
+	i.once.Do(func() {
+		i.app.Shutdown(instrumentationFlushTimeout)
+	})
+}
+
+// ShutdownInstrumentation sends the data recorded by the instrumentation of
+// the providers configured in this process, if any. It is meant to be called
+// once Terraform is done with the provider, as Terraform only cancels the
+// provider's stop context when it is interrupted.
+func ShutdownInstrumentation() {
+	instrumentations.Lock()
+	all := instrumentations.all
+	instrumentations.all = nil
+	instrumentations.Unlock()
+
+	var wg sync.WaitGroup
+	for _, i := range all {
+		wg.Add(1)
+		go func(i *instrumentation) {
+			defer wg.Done()
+			i.shutdown()
+		}(i)
+	}
+	wg.Wait()
+}
+
+// Wraps the functions of a resource so that each operation is recorded as a
+// transaction named after the resource and operation, e.g.
+// newrelic_alert_policy/create, when the provider is instrumented. The
+// requests made by the operation are recorded as its external segments.
+func instrumentOperations(name string, r *schema.Resource) {
+	instrument := func(operation string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if f == nil {
+			return nil
+		}
+
+		return func(d *schema.ResourceData, meta interface{}) error {
+			providerConfig, ok := meta.(*ProviderConfig)
+			if !ok || providerConfig.instrumentation == nil {
+				return f(d, meta)
+			}
+
+			txn := providerConfig.instrumentation.app.StartTransaction(name + "/" + operation)
+			defer txn.End()
+
+			// Clients are made for each operation from the provider's stop
+			// context, so the transaction reaches their requests through a
+			// copy of the provider config holding it.
+			stopCtx := providerConfig.stopCtx
+			if stopCtx == nil {
+				stopCtx = context.Background()
+			}
+
+			operationConfig := *providerConfig
+			operationConfig.stopCtx = agent.NewContext(stopCtx, txn)
+
+			err := f(d, &operationConfig)
+			if err != nil {
+				txn.NoticeError(err)
+			}
+
+			txn.AddAttribute("resourceId", d.Id())
+
+			return err
+		}
+	}
+
+	r.Create = instrument("create", r.Create)
+	r.Read = instrument("read", r.Read)
+	r.Update = instrument("update", r.Update)
+	r.Delete = instrument("delete", r.Delete)
+}
@@ -0,0 +1,167 @@
+package newrelic
+
+import (
+	"compress/gzip"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/require"
+)
+
+// testCollector is a stand-in for the New Relic collector, which records the
+// payload of every command the agent sends.
+type testCollector struct {
+	sync.Mutex
+	server   *httptest.Server
+	payloads map[string]string
+	calls    map[string]int
+}
+
+func newTestCollector(t *testing.T) *testCollector {
+	c := &testCollector{payloads: map[string]string{}, calls: map[string]int{}}
+
+	c.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+
+		payload, err := ioutil.ReadAll(body)
+		require.NoError(t, err)
+
+		method := r.URL.Query().Get("method")
+
+		c.Lock()
+		c.payloads[method] += string(payload)
+		c.calls[method]++
+		c.Unlock()
+
+		switch method {
+		case "preconnect":
+			_, _ = w.Write([]byte(`{"return_value":{"redirect_host":"` + c.host() + `"}}`))
+		case "connect":
+			_, _ = w.Write([]byte(`{"return_value":{"agent_run_id":"tf-test-run"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"return_value":null}`))
+		}
+	}))
+
+	return c
+}
+
+func (c *testCollector) host() string {
+	u, _ := url.Parse(c.server.URL)
+	return u.Host
+}
+
+func (c *testCollector) certificate() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.server.Certificate().Raw}))
+}
+
+func (c *testCollector) payload(method string) string {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.payloads[method]
+}
+
+func (c *testCollector) callCount(method string) int {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.calls[method]
+}
+
+func TestInstrumentOperations(t *testing.T) {
+	collector := newTestCollector(t)
+	defer collector.server.Close()
+
+	srv := newMockAPIServer()
+	defer srv.Close()
+
+	cfg := srv.config()
+	cfg.CACertFile = collector.certificate()
+
+	instrumentation, err := expandInstrumentation([]interface{}{map[string]interface{}{
+		"enabled":     true,
+		"license_key": strings.Repeat("0", 40),
+		"app_name":    "tf-test",
+		"host":        collector.host(),
+	}}, &cfg)
+	require.NoError(t, err)
+	require.NotNil(t, instrumentation)
+
+	// Operations run before the agent has connected are not recorded.
+	require.NoError(t, instrumentation.app.WaitForConnection(10*time.Second))
+
+	cfg.Instrumented = true
+
+	client, err := cfg.Client()
+	require.NoError(t, err)
+
+	meta := &ProviderConfig{
+		NewClient:       client,
+		AccountID:       mockAccountID,
+		PersonalAPIKey:  mockPersonalAPIKey,
+		clientConfig:    &cfg,
+		instrumentation: instrumentation,
+	}
+
+	r := resourceNewRelicAlertPolicy()
+	instrumentOperations("newrelic_alert_policy", r)
+
+	for _, name := range []string{"tf-test-1", "tf-test-2"} {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"name": name})
+		require.NoError(t, r.Create(d, meta))
+	}
+
+	// The data is sent once, when the provider stops, by the application
+	// that recorded every operation.
+	require.Empty(t, collector.payload("analytic_event_data"))
+
+	instrumentation.shutdown()
+
+	require.Contains(t, collector.payload("analytic_event_data"), "OtherTransaction/Go/newrelic_alert_policy/create")
+	require.Contains(t, collector.payload("metric_data"), "External/"+strings.TrimPrefix(srv.URL(), "http://")+"/all")
+	require.Equal(t, 1, collector.callCount("connect"))
+	require.Equal(t, 1, collector.callCount("analytic_event_data"))
+}
+
+func TestInstrumentOperations_CollectorUnreachable(t *testing.T) {
+	start := time.Now()
+
+	instrumentation, err := expandInstrumentation([]interface{}{map[string]interface{}{
+		"enabled":     true,
+		"license_key": strings.Repeat("0", 40),
+		"app_name":    "tf-test",
+		"host":        "127.0.0.1:1",
+	}}, &Config{})
+	require.NoError(t, err)
+	defer instrumentation.shutdown()
+
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		Read:   func(*schema.ResourceData, interface{}) error { return nil },
+	}
+	instrumentOperations("newrelic_alert_policy", r)
+
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	require.NoError(t, r.Read(d, &ProviderConfig{instrumentation: instrumentation}))
+	require.True(t, time.Since(start) < time.Second, "waited %s for the collector", time.Since(start))
+}
+
+func TestExpandInstrumentation_Disabled(t *testing.T) {
+	instrumentation, err := expandInstrumentation([]interface{}{}, &Config{})
+	require.NoError(t, err)
+	require.Nil(t, instrumentation)
+
+	instrumentation, err = expandInstrumentation([]interface{}{map[string]interface{}{"enabled": false}}, &Config{})
+	require.NoError(t, err)
+	require.Nil(t, instrumentation)
+}
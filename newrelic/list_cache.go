@@ -0,0 +1,201 @@
+package newrelic
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/apm"
+	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+)
+
+// listCache holds the results of list calls for the rest of a run, so that
+// resources and data sources looking up objects by name or ID share a single
+// call per list instead of making one each. Every change made through the
+// client clears the whole cache, as any of them can change what a list
+// returns.
+//
+// Cached results are shared between callers, which must not modify them.
+type listCache struct {
+	mu      sync.Mutex
+	entries map[string]*listCacheEntry
+
+	// generation counts the times the cache was cleared, so that lists
+	// started before a change are not cached once it is made.
+	generation int
+}
+
+type listCacheEntry struct {
+	sync.Mutex
+	value  interface{}
+	cached bool
+}
+
+func newListCache() *listCache {
+	return &listCache{entries: map[string]*listCacheEntry{}}
+}
+
+// get returns the cached result for the key, calling list when there is none.
+// Callers asking for the same key wait for the first one's call instead of
+// making their own. Errors are returned but not cached. A nil cache always
+// calls list.
+func (c *listCache) get(key string, list func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return list()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &listCacheEntry{}
+		c.entries[key] = entry
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	entry.Lock()
+	defer entry.Unlock()
+
+	if entry.cached {
+		return entry.value, nil
+	}
+
+	value, err := list()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		entry.value = value
+		entry.cached = true
+	}
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// clear drops every cached result.
+func (c *listCache) clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.entries = map[string]*listCacheEntry{}
+	c.generation++
+	c.mu.Unlock()
+}
+
+// listCacheTransport clears the list cache once a request that may change
+// objects has been sent, whether or not it succeeded.
+type listCacheTransport struct {
+	transport http.RoundTripper
+	cache     *listCache
+}
+
+func (t *listCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cache == nil {
+		return t.transport.RoundTrip(req)
+	}
+
+	var body []byte
+
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+
+	if changesObjects(req, body) {
+		t.cache.clear()
+	}
+
+	return resp, err
+}
+
+// changesObjects reports whether a request may create, update or delete
+// objects.
+func changesObjects(req *http.Request, body []byte) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	case http.MethodPost:
+		return !isNerdGraphQuery(body)
+	}
+
+	return true
+}
+
+// listAlertChannels returns every alert channel.
+func (c *ProviderConfig) listAlertChannels(client *nr.NewRelic) ([]*alerts.Channel, error) {
+	channels, err := c.listCache.get("alert_channels", func() (interface{}, error) {
+		return client.Alerts.ListChannels()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return channels.([]*alerts.Channel), nil
+}
+
+// listAlertPolicies returns the alert policies whose name contains the given
+// one, using the REST API.
+func (c *ProviderConfig) listAlertPolicies(client *nr.NewRelic, name string) ([]alerts.Policy, error) {
+	policies, err := c.listCache.get("alert_policies/"+name, func() (interface{}, error) {
+		return client.Alerts.ListPolicies(&alerts.ListPoliciesParams{Name: name})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policies.([]alerts.Policy), nil
+}
+
+// searchAlertPolicies returns every alert policy of an account, using
+// NerdGraph.
+func (c *ProviderConfig) searchAlertPolicies(client *nr.NewRelic, accountID int) ([]*alerts.AlertsPolicy, error) {
+	policies, err := c.listCache.get(fmt.Sprintf("alert_policies_search/%d", accountID), func() (interface{}, error) {
+		return client.Alerts.QueryPolicySearch(accountID, alerts.AlertsPoliciesSearchCriteriaInput{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policies.([]*alerts.AlertsPolicy), nil
+}
+
+// listApplications returns the APM applications whose name contains the
+// given one. Accounts can hold far more applications than are looked up, so
+// lists are filtered by name rather than listing every application once.
+func (c *ProviderConfig) listApplications(client *nr.NewRelic, name string) ([]*apm.Application, error) {
+	applications, err := c.listCache.get("applications/"+name, func() (interface{}, error) {
+		return client.APM.ListApplications(&apm.ListApplicationsParams{Name: name})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return applications.([]*apm.Application), nil
+}
+
+// listSyntheticsMonitors returns every Synthetics monitor.
+func (c *ProviderConfig) listSyntheticsMonitors(client *nr.NewRelic) ([]*synthetics.Monitor, error) {
+	monitors, err := c.listCache.get("synthetics_monitors", func() (interface{}, error) {
+		return client.Synthetics.ListMonitors()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return monitors.([]*synthetics.Monitor), nil
+}
@@ -0,0 +1,157 @@
+package newrelic
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCache(t *testing.T) {
+	cache := newListCache()
+	var calls int32
+
+	list := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return []string{"a"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			v, err := cache.get("letters", list)
+			require.NoError(t, err)
+			require.Equal(t, []string{"a"}, v)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	cache.clear()
+
+	_, err := cache.get("letters", list)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestListCache_Errors(t *testing.T) {
+	cache := newListCache()
+
+	_, err := cache.get("letters", func() (interface{}, error) {
+		return nil, errors.New("unavailable")
+	})
+	require.EqualError(t, err, "unavailable")
+
+	v, err := cache.get("letters", func() (interface{}, error) {
+		return []string{"a"}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, v)
+}
+
+func TestListCache_ClearedWhileListing(t *testing.T) {
+	cache := newListCache()
+
+	_, err := cache.get("letters", func() (interface{}, error) {
+		cache.clear()
+		return []string{"a"}, nil
+	})
+	require.NoError(t, err)
+
+	v, err := cache.get("letters", func() (interface{}, error) {
+		return []string{"b"}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, v)
+}
+
+func TestListCache_Nil(t *testing.T) {
+	var cache *listCache
+	var calls int
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.get("letters", func() (interface{}, error) {
+			calls++
+			return nil, nil
+		})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 2, calls)
+	cache.clear()
+}
+
+func TestChangesObjects(t *testing.T) {
+	cases := []struct {
+		method string
+		body   string
+		want   bool
+	}{
+		{http.MethodGet, "", false},
+		{http.MethodPost, `{"query":"{ actor { user { id } } }"}`, false},
+		{http.MethodPost, `{"query":"mutation { alertsPolicyDelete(accountId: 1, id: 1) { id } }"}`, true},
+		{http.MethodPost, `{"channel":{"name":"foo"}}`, true},
+		{http.MethodPut, "", true},
+		{http.MethodDelete, "", true},
+	}
+
+	for _, tc := range cases {
+		req, err := http.NewRequest(tc.method, "https://api.newrelic.com", strings.NewReader(tc.body))
+		require.NoError(t, err)
+		require.Equal(t, tc.want, changesObjects(req, []byte(tc.body)), "%s %s", tc.method, tc.body)
+	}
+}
+
+func TestProviderConfig_ListAlertChannels(t *testing.T) {
+	var lists int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodGet:
+			atomic.AddInt32(&lists, 1)
+			_, _ = w.Write([]byte(`{"channels":[{"id":1,"name":"foo","type":"email","links":{"policy_ids":[]}}]}`))
+		case http.MethodDelete:
+			_, _ = w.Write([]byte(`{"channel":{"id":1,"name":"foo","type":"email","links":{"policy_ids":[]}}}`))
+		}
+	}))
+	defer srv.Close()
+
+	cfg := Config{
+		AdminAPIKey: "abc123",
+		APIURL:      srv.URL,
+		userAgent:   "terraform-provider-newrelic-test",
+		listCache:   newListCache(),
+	}
+
+	client, err := cfg.Client()
+	require.NoError(t, err)
+
+	providerConfig := &ProviderConfig{NewClient: client, listCache: cfg.listCache}
+
+	for i := 0; i < 3; i++ {
+		channels, err := providerConfig.listAlertChannels(client)
+		require.NoError(t, err)
+		require.Equal(t, []*alerts.Channel{{ID: 1, Name: "foo", Type: "email", Links: alerts.ChannelLinks{PolicyIDs: []int{}}}}, channels)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&lists))
+
+	// Changes made through the client are followed by a fresh list.
+	_, err = client.Alerts.DeleteChannel(1)
+	require.NoError(t, err)
+
+	_, err = providerConfig.listAlertChannels(client)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&lists))
+}
@@ -0,0 +1,406 @@
+package newrelic
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/logging"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// The value secrets are replaced with in logs.
+const redacted = "[REDACTED]"
+
+// Headers that hold credentials, in their canonical form.
+var secretHeaders = []string{
+	"Api-Key",
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Insert-Key",
+	"X-License-Key",
+	"X-Query-Key",
+}
+
+// Fields of API payloads that hold secrets, whichever API they are sent to.
+var secretFields = []string{
+	"api_key",
+	"auth_password",
+	"insert_key",
+	"license_key",
+	"password",
+	"personal_api_key",
+	"query_key",
+	"secret",
+	"token",
+}
+
+// The APIs through which the resources and data sources with sensitive
+// attributes are managed, by a part of their path, along with the keys of the
+// API payloads that their blocks are sent under. The values of sensitive
+// attributes are only redacted from the payloads of their API, as their names
+// are too generic to be secrets in every payload, such as the `value` of an
+// alert condition. Those of other resources are redacted from every payload.
+var sensitiveAttributeAPIs = map[string]struct {
+	path   string
+	blocks map[string]string
+}{
+	"newrelic_alert_channel": {
+		path:   "/alerts_channels",
+		blocks: map[string]string{"config": "configuration"},
+	},
+	"newrelic_synthetics_secure_credential": {
+		path: "/secure-credentials",
+	},
+}
+
+// sensitiveAPIFields returns the paths of the fields of API payloads that hold
+// the values of sensitive attributes, by a part of the path of their API, or
+// by "" for every API. Each field is given as the keys leading to it, which
+// match wherever they are nested: `configuration.url` matches both the channel
+// sent to the API and each of the channels it lists.
+func sensitiveAPIFields(resources ...map[string]*schema.Resource) map[string][]string {
+	found := map[string]map[string]bool{}
+
+	for _, m := range resources {
+		for name, r := range m {
+			api := sensitiveAttributeAPIs[name]
+
+			replaced := map[string]bool{}
+			for _, b := range api.blocks {
+				replaced[b] = true
+			}
+
+			var walk func(prefix string, s map[string]*schema.Schema)
+			walk = func(prefix string, s map[string]*schema.Schema) {
+				for k, attr := range s {
+					if elem, ok := attr.Elem.(*schema.Resource); ok {
+						block := k
+						if b, ok := api.blocks[k]; ok {
+							block = b
+						}
+
+						walk(prefix+block+".", elem.Schema)
+						continue
+					}
+
+					// A map replaced by a block, such as the deprecated
+					// `configuration` of alert channels, is left to the
+					// attributes of the block.
+					if attr.Sensitive && !(attr.Type == schema.TypeMap && replaced[k]) {
+						if found[api.path] == nil {
+							found[api.path] = map[string]bool{}
+						}
+						found[api.path][prefix+k] = true
+					}
+				}
+			}
+			walk("", r.Schema)
+		}
+	}
+
+	fields := make(map[string][]string, len(found))
+	for path, names := range found {
+		for name := range names {
+			fields[path] = append(fields[path], name)
+		}
+		sort.Strings(fields[path])
+	}
+
+	return fields
+}
+
+// loggingTransport logs the details of each request and response when debug
+// logging is enabled, like the transport of the SDK's logging package, but
+// with the value of every secret header, query parameter and JSON field
+// replaced before anything is written.
+type loggingTransport struct {
+	name      string
+	transport http.RoundTripper
+
+	// Field names normalized with redactionKey.
+	fields map[string]bool
+
+	// The paths of the secret fields of each API, by a part of its path,
+	// each as its keys normalized with redactionKey.
+	apiFields map[string][][]string
+}
+
+// newLoggingTransport returns a transport logging with the given name, which
+// also redacts the given fields of each API, as returned by
+// sensitiveAPIFields. Field names are matched regardless of case, underscores
+// and dashes, so that `auth_password` also matches the `authPassword` field of
+// a NerdGraph payload.
+func newLoggingTransport(name string, t http.RoundTripper, apiFields map[string][]string) *loggingTransport {
+	lt := &loggingTransport{
+		name:      name,
+		transport: t,
+		fields:    map[string]bool{},
+		apiFields: map[string][][]string{},
+	}
+
+	for _, f := range secretFields {
+		lt.fields[redactionKey(f)] = true
+	}
+
+	for path, fields := range apiFields {
+		for _, f := range fields {
+			keys := strings.Split(f, ".")
+			for i, k := range keys {
+				keys[i] = redactionKey(k)
+			}
+
+			lt.apiFields[path] = append(lt.apiFields[path], keys)
+		}
+	}
+
+	return lt
+}
+
+// fieldsOf returns the paths of the secret fields of the API at the given
+// URL.
+func (t *loggingTransport) fieldsOf(u *url.URL) [][]string {
+	paths := t.apiFields[""]
+
+	if u == nil {
+		return paths
+	}
+
+	for path, fields := range t.apiFields {
+		if path != "" && strings.Contains(u.Path, path) {
+			paths = append(paths[:len(paths):len(paths)], fields...)
+		}
+	}
+
+	return paths
+}
+
+func redactionKey(name string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if logging.IsDebugOrHigher() {
+		if err := t.logRequest(req); err != nil {
+			log.Printf("[ERROR] %s API Request error: %#v", t.name, err)
+		}
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if logging.IsDebugOrHigher() {
+		if err := t.logResponse(resp); err != nil {
+			log.Printf("[ERROR] %s API Response error: %#v", t.name, err)
+		}
+	}
+
+	return resp, nil
+}
+
+// logRequest logs a redacted copy of the request, leaving the request itself
+// as it was.
+func (t *loggingTransport) logRequest(req *http.Request) error {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return err
+	}
+
+	logged := req.Clone(req.Context())
+	logged.Header = t.redactHeader(req.Header)
+	logged.URL.RawQuery = t.redactQuery(req.URL.RawQuery)
+	logged.Body = ioutil.NopCloser(bytes.NewReader(t.redactBody(req.Header, body, t.fieldsOf(req.URL))))
+	logged.ContentLength = -1
+
+	dump, err := httputil.DumpRequestOut(logged, true)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] "+logRequestMessage, t.name, prettyPrintJSONLines(dump))
+
+	return nil
+}
+
+// logResponse logs a redacted copy of the response, leaving the response
+// itself as it was.
+func (t *loggingTransport) logResponse(resp *http.Response) error {
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var u *url.URL
+	if resp.Request != nil {
+		u = resp.Request.URL
+	}
+
+	logged := *resp
+	logged.Header = t.redactHeader(resp.Header)
+	logged.Body = ioutil.NopCloser(bytes.NewReader(t.redactBody(resp.Header, body, t.fieldsOf(u))))
+	logged.ContentLength = -1
+
+	dump, err := httputil.DumpResponse(&logged, true)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] "+logResponseMessage, t.name, prettyPrintJSONLines(dump))
+
+	return nil
+}
+
+// readBody reads a request or response body, replacing it with a copy that
+// can still be read by whoever reads it next.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	*body = ioutil.NopCloser(bytes.NewReader(b))
+
+	return b, err
+}
+
+func (t *loggingTransport) redactHeader(header http.Header) http.Header {
+	logged := header.Clone()
+
+	for _, h := range secretHeaders {
+		if _, ok := logged[h]; ok {
+			logged.Set(h, redacted)
+		}
+	}
+
+	return logged
+}
+
+func (t *loggingTransport) redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redacted
+	}
+
+	for k := range query {
+		if t.fields[redactionKey(k)] {
+			query.Set(k, redacted)
+		}
+	}
+
+	return query.Encode()
+}
+
+// redactBody returns a copy of a JSON body with the value of every secret
+// field replaced, including the fields at the given paths of keys. Bodies
+// that cannot be checked for secrets are not logged.
+func (t *loggingTransport) redactBody(header http.Header, body []byte, paths [][]string) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	if encoding := header.Get("Content-Encoding"); encoding != "" && encoding != "identity" {
+		return []byte("[" + encoding + " encoded body not logged]")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return []byte("[non-JSON body not logged]")
+	}
+
+	b, err := json.Marshal(t.redactValue(v, nil, paths))
+	if err != nil {
+		return []byte("[body not logged]")
+	}
+
+	return b
+}
+
+// redactValue redacts a value found under the given keys of a payload, in
+// place.
+func (t *loggingTransport) redactValue(v interface{}, keys []string, paths [][]string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			fieldKeys := append(keys[:len(keys):len(keys)], redactionKey(k))
+
+			if t.isSecret(fieldKeys, paths) && field != nil && field != "" {
+				v[k] = redacted
+			} else {
+				v[k] = t.redactValue(field, fieldKeys, paths)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = t.redactValue(item, keys, paths)
+		}
+	}
+
+	return v
+}
+
+// isSecret reports whether the field under the given keys is a secret, which
+// it is when its name is that of a secret field, or when its keys end with
+// one of the given paths.
+func (t *loggingTransport) isSecret(keys []string, paths [][]string) bool {
+	if t.fields[keys[len(keys)-1]] {
+		return true
+	}
+
+	for _, p := range paths {
+		if len(p) > len(keys) {
+			continue
+		}
+
+		if strings.Join(keys[len(keys)-len(p):], ".") == strings.Join(p, ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prettyPrintJSONLines pretty-prints the lines of a dump that are complete
+// JSON documents.
+func prettyPrintJSONLines(b []byte) string {
+	parts := strings.Split(string(b), "\n")
+
+	for i, p := range parts {
+		if b := []byte(p); json.Valid(b) {
+			var out bytes.Buffer
+			if err := json.Indent(&out, b, "", " "); err == nil {
+				parts[i] = out.String()
+			}
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+const logRequestMessage = `%s API Request Details:
+---[ REQUEST ]---------------------------------------
+%s
+-----------------------------------------------------`
+
+const logResponseMessage = `%s API Response Details:
+---[ RESPONSE ]--------------------------------------
+%s
+-----------------------------------------------------`
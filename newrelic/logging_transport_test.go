@@ -0,0 +1,160 @@
+package newrelic
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/logging"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func testSensitiveAPIFields() map[string][]string {
+	p := Provider().(*schema.Provider)
+	return sensitiveAPIFields(p.ResourcesMap, p.DataSourcesMap)
+}
+
+func TestLoggingTransport(t *testing.T) {
+	defer os.Setenv(logging.EnvLog, os.Getenv(logging.EnvLog))
+	os.Setenv(logging.EnvLog, "DEBUG")
+
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	requestBody := `{"channel":{"configuration":{"auth_password":"hunter2","user":"admin","url":"https://hooks.example.com/s3cr3t"}},"variables":{"authPassword":"hunter3","items":[{"api-key":"abc123"}]}}`
+	responseBody := `{"channels":[{"configuration":{"key":"v1ct0r0ps","channel":"#alerts"}}]}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, requestBody, string(body))
+		require.Equal(t, "NRAK-SECRET", r.Header.Get("X-Api-Key"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=s3cr3t")
+		_, _ = w.Write([]byte(responseBody))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newLoggingTransport("newrelic", http.DefaultTransport, testSensitiveAPIFields())}
+
+	req, err := http.NewRequest("POST", srv.URL+"/v2/alerts_channels.json?filter[name]=test&api_key=NRAK-SECRET", strings.NewReader(requestBody))
+	require.NoError(t, err)
+	req.Header.Set("X-Api-Key", "NRAK-SECRET")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, responseBody, string(body))
+
+	out := logs.String()
+	require.Contains(t, out, "newrelic API Request Details")
+	require.Contains(t, out, "newrelic API Response Details")
+	require.Contains(t, out, `"user": "admin"`)
+	require.Contains(t, out, `"channel": "#alerts"`)
+	require.Contains(t, out, "filter%5Bname%5D=test")
+
+	for _, secret := range []string{"NRAK-SECRET", "hunter2", "hunter3", "abc123", "s3cr3t", "v1ct0r0ps"} {
+		require.NotContains(t, out, secret)
+	}
+}
+
+func TestLoggingTransport_RedactBody(t *testing.T) {
+	lt := newLoggingTransport("newrelic", http.DefaultTransport, testSensitiveAPIFields())
+
+	require.Equal(t, `{"api_key":"[REDACTED]","id":12345678901234567890,"password":""}`,
+		string(lt.redactBody(http.Header{}, []byte(`{"api_key":"secret","id":12345678901234567890,"password":""}`), nil)))
+	require.Equal(t, "[non-JSON body not logged]", string(lt.redactBody(http.Header{}, []byte("api_key=secret"), nil)))
+	require.Equal(t, "[gzip encoded body not logged]", string(lt.redactBody(http.Header{"Content-Encoding": {"gzip"}}, []byte("..."), nil)))
+}
+
+func TestLoggingTransport_RedactBodyOfAPI(t *testing.T) {
+	lt := newLoggingTransport("newrelic", http.DefaultTransport, testSensitiveAPIFields())
+
+	credentials, err := url.Parse("https://synthetics.newrelic.com/synthetics/api/v1/secure-credentials")
+	require.NoError(t, err)
+
+	require.Equal(t, `{"description":"","key":"TOKEN","value":"[REDACTED]"}`,
+		string(lt.redactBody(http.Header{}, []byte(`{"key":"TOKEN","value":"s3cr3t","description":""}`), lt.fieldsOf(credentials))))
+
+	// Generic names are only secrets for the API they are listed for.
+	conditions, err := url.Parse("https://api.newrelic.com/v2/alerts_conditions/policies/1.json")
+	require.NoError(t, err)
+
+	require.Equal(t, `{"condition":{"terms":[{"value":"90"}],"user_defined":{"key":"cpu","value":"80"}}}`,
+		string(lt.redactBody(http.Header{}, []byte(`{"condition":{"terms":[{"value":"90"}],"user_defined":{"key":"cpu","value":"80"}}}`), lt.fieldsOf(conditions))))
+
+	channels, err := url.Parse("https://api.newrelic.com/v2/alerts_channels.json")
+	require.NoError(t, err)
+
+	require.Equal(t, `{"channel":{"configuration":{"channel":"#alerts","url":"[REDACTED]"},"name":"slack","url":"https://example.com"}}`,
+		string(lt.redactBody(http.Header{}, []byte(`{"channel":{"name":"slack","url":"https://example.com","configuration":{"url":"https://hooks.slack.com/s3cr3t","channel":"#alerts"}}}`), lt.fieldsOf(channels))))
+}
+
+func TestSensitiveAPIFields(t *testing.T) {
+	require.Equal(t, map[string][]string{
+		"/alerts_channels": {"configuration.api_key", "configuration.headers"},
+		"":                 {"token"},
+	}, sensitiveAPIFields(map[string]*schema.Resource{
+		"newrelic_alert_channel": {Schema: map[string]*schema.Schema{
+			"name":          {Type: schema.TypeString},
+			"configuration": {Type: schema.TypeMap, Sensitive: true},
+			"config": {Type: schema.TypeList, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"api_key": {Type: schema.TypeString, Sensitive: true},
+				"headers": {Type: schema.TypeMap, Sensitive: true},
+				"channel": {Type: schema.TypeString},
+			}}},
+		}},
+		"newrelic_example": {Schema: map[string]*schema.Schema{
+			"token": {Type: schema.TypeString, Sensitive: true},
+		}},
+	}))
+}
+
+// Every sensitive attribute of the provider's resources and data sources is
+// redacted from the payloads of their API, and only of their API.
+func TestLoggingTransport_RedactsSensitiveAttributes(t *testing.T) {
+	p := Provider().(*schema.Provider)
+	lt := newLoggingTransport("newrelic", http.DefaultTransport, testSensitiveAPIFields())
+
+	for _, m := range []map[string]*schema.Resource{p.ResourcesMap, p.DataSourcesMap} {
+		for name, r := range m {
+			fields := sensitiveAPIFields(map[string]*schema.Resource{name: r})
+			if len(fields) == 0 {
+				continue
+			}
+
+			api, ok := sensitiveAttributeAPIs[name]
+			require.True(t, ok, "%s has sensitive attributes, list its API in sensitiveAttributeAPIs", name)
+
+			u, err := url.Parse("https://api.newrelic.com/v2" + api.path + ".json")
+			require.NoError(t, err)
+
+			for _, f := range fields[api.path] {
+				var payload interface{} = "s3cr3t"
+				keys := strings.Split(f, ".")
+				for i := len(keys) - 1; i >= 0; i-- {
+					payload = map[string]interface{}{keys[i]: payload}
+				}
+
+				body, err := json.Marshal(map[string]interface{}{"items": []interface{}{payload}})
+				require.NoError(t, err)
+
+				require.NotContains(t, string(lt.redactBody(http.Header{}, body, lt.fieldsOf(u))), "s3cr3t", "%s: %s", name, f)
+			}
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package newrelic
+
+import (
+	"encoding/json"
+	"fmt"
+
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdgraph"
+)
+
+// newrelic-client-go only supports the fields that NRQL conditions of every
+// type have had from the start, so the fields of outlier conditions and the
+// settings of their signal are managed with the queries below.
+//
+// NerdGraph.Query only decodes the `actor` field of a response, so the result
+// of each mutation is aliased to it.
+
+// nrqlConditionSignal holds how the data points of a NRQL condition are
+// aggregated, and how gaps between them are filled.
+type nrqlConditionSignal struct {
+	AggregationWindow *int     `json:"aggregationWindow,omitempty"`
+	FillOption        *string  `json:"fillOption,omitempty"`
+	FillValue         *float64 `json:"fillValue"`
+}
+
+// nrqlConditionExpiration holds what happens when the signal of a NRQL
+// condition stops reporting data.
+type nrqlConditionExpiration struct {
+	CloseViolationsOnExpiration bool `json:"closeViolationsOnExpiration"`
+	ExpirationDuration          *int `json:"expirationDuration"`
+	OpenViolationOnExpiration   bool `json:"openViolationOnExpiration"`
+}
+
+// nrqlConditionSignalSettings are the signal and loss of signal settings that
+// NRQL conditions of every type have.
+type nrqlConditionSignalSettings struct {
+	Signal     *nrqlConditionSignal     `json:"signal,omitempty"`
+	Expiration *nrqlConditionExpiration `json:"expiration,omitempty"`
+}
+
+// nrqlConditionInput is the input of a static or baseline NRQL condition.
+type nrqlConditionInput struct {
+	alerts.NrqlConditionInput
+	nrqlConditionSignalSettings
+}
+
+// nrqlConditionOutlierInput is the input of an outlier NRQL condition.
+type nrqlConditionOutlierInput struct {
+	alerts.NrqlConditionBase
+	nrqlConditionSignalSettings
+
+	ExpectedGroups              int  `json:"expectedGroups"`
+	OpenViolationOnGroupOverlap bool `json:"openViolationOnGroupOverlap"`
+}
+
+// nerdGraphNrqlCondition is a NRQL condition of any type read from NerdGraph.
+type nerdGraphNrqlCondition struct {
+	alerts.NrqlAlertCondition
+	nrqlConditionSignalSettings
+
+	// ExpectedGroups and OpenViolationOnGroupOverlap exist ONLY for NRQL
+	// conditions of type OUTLIER.
+	ExpectedGroups              *int  `json:"expectedGroups,omitempty"`
+	OpenViolationOnGroupOverlap *bool `json:"openViolationOnGroupOverlap,omitempty"`
+}
+
+// The names NRQL condition types have in the mutations and inputs of
+// NerdGraph.
+var nrqlConditionMutationTypes = map[string]string{
+	"static":   "Static",
+	"baseline": "Baseline",
+	"outlier":  "Outlier",
+}
+
+const (
+	graphqlNrqlConditionFields = `
+		id
+		name
+		nrql {
+			evaluationOffset
+			query
+		}
+		enabled
+		description
+		policyId
+		runbookUrl
+		terms {
+			operator
+			priority
+			threshold
+			thresholdDuration
+			thresholdOccurrences
+		}
+		type
+		violationTimeLimit
+		signal {
+			aggregationWindow
+			fillOption
+			fillValue
+		}
+		expiration {
+			closeViolationsOnExpiration
+			expirationDuration
+			openViolationOnExpiration
+		}
+		... on AlertsNrqlBaselineCondition {
+			baselineDirection
+		}
+		... on AlertsNrqlStaticCondition {
+			valueFunction
+		}
+		... on AlertsNrqlOutlierCondition {
+			expectedGroups
+			openViolationOnGroupOverlap
+		}
+	`
+
+	getNrqlConditionQuery = `
+		query($accountId: Int!, $id: ID!) {
+			actor {
+				account(id: $accountId) {
+					alerts {
+						nrqlCondition(id: $id) {` + graphqlNrqlConditionFields + `}
+					}
+				}
+			}
+		}`
+
+	// The type of the condition is filled in with nrqlConditionMutationTypes.
+	createNrqlConditionMutation = `
+		mutation($accountId: Int!, $policyId: ID!, $condition: AlertsNrqlCondition%[1]sInput!) {
+			actor: alertsNrqlCondition%[1]sCreate(accountId: $accountId, policyId: $policyId, condition: $condition) {` +
+		graphqlNrqlConditionFields + `}
+		}`
+
+	updateNrqlConditionMutation = `
+		mutation($accountId: Int!, $id: ID!, $condition: AlertsNrqlConditionUpdate%[1]sInput!) {
+			actor: alertsNrqlCondition%[1]sUpdate(accountId: $accountId, id: $id, condition: $condition) {` +
+		graphqlNrqlConditionFields + `}
+		}`
+)
+
+// getNrqlCondition returns a NRQL condition of any type, including the fields
+// of outlier conditions.
+func getNrqlCondition(client *nr.NewRelic, accountID int, conditionID string) (*nerdGraphNrqlCondition, error) {
+	var actor struct {
+		Account struct {
+			Alerts struct {
+				NrqlCondition nerdGraphNrqlCondition `json:"nrqlCondition"`
+			} `json:"alerts"`
+		} `json:"account"`
+	}
+
+	vars := map[string]interface{}{
+		"accountId": accountID,
+		"id":        conditionID,
+	}
+
+	if err := queryNerdGraphActor(client, getNrqlConditionQuery, vars, &actor); err != nil {
+		return nil, err
+	}
+
+	return &actor.Account.Alerts.NrqlCondition, nil
+}
+
+// createNrqlCondition creates a NRQL condition of the given type in a policy.
+// The input is a nrqlConditionInput, or a nrqlConditionOutlierInput for
+// outlier conditions.
+func createNrqlCondition(client *nr.NewRelic, accountID int, conditionType string, policyID string, input interface{}) (*nerdGraphNrqlCondition, error) {
+	condition := nerdGraphNrqlCondition{}
+	vars := map[string]interface{}{
+		"accountId": accountID,
+		"policyId":  policyID,
+		"condition": input,
+	}
+
+	mutation := fmt.Sprintf(createNrqlConditionMutation, nrqlConditionMutationTypes[conditionType])
+	if err := queryNerdGraphActor(client, mutation, vars, &condition); err != nil {
+		return nil, err
+	}
+
+	return &condition, nil
+}
+
+// updateNrqlCondition updates a NRQL condition of the given type.
+func updateNrqlCondition(client *nr.NewRelic, accountID int, conditionType string, conditionID string, input interface{}) (*nerdGraphNrqlCondition, error) {
+	condition := nerdGraphNrqlCondition{}
+	vars := map[string]interface{}{
+		"accountId": accountID,
+		"id":        conditionID,
+		"condition": input,
+	}
+
+	mutation := fmt.Sprintf(updateNrqlConditionMutation, nrqlConditionMutationTypes[conditionType])
+	if err := queryNerdGraphActor(client, mutation, vars, &condition); err != nil {
+		return nil, err
+	}
+
+	return &condition, nil
+}
+
+// queryNerdGraphActor makes a NerdGraph request and decodes the `actor` field
+// of its response into v.
+func queryNerdGraphActor(client *nr.NewRelic, query string, vars map[string]interface{}, v interface{}) error {
+	resp, err := client.NerdGraph.Query(query, vars)
+	if err != nil {
+		return err
+	}
+
+	actor, err := json.Marshal(resp.(nerdgraph.QueryResponse).Actor)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(actor, v)
+}
@@ -1,10 +1,14 @@
 package newrelic
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/mutexkv"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/httpclient"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 
@@ -14,11 +18,6 @@ import (
 // TerraformProviderProductUserAgent string used to identify this provider in User Agent requests
 const TerraformProviderProductUserAgent = "terraform-provider-newrelic"
 
-const (
-	insightsInsertURL = "https://insights-collector.newrelic.com/v1/accounts"
-	insightsQueryURL  = "https://insights-api.newrelic.com/v1/accounts"
-)
-
 // Provider represents a resource provider in Terraform
 func Provider() terraform.ResourceProvider {
 	provider := &schema.Provider{
@@ -41,6 +40,12 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_PERSONAL_API_KEY", nil),
 				Sensitive:   true,
 			},
+			"region": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NEWRELIC_REGION", nil),
+				ValidateFunc: validation.StringInSlice(regionNames, true),
+			},
 			"api_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -76,7 +81,7 @@ func Provider() terraform.ResourceProvider {
 			"insights_insert_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_INSIGHTS_INSERT_URL", insightsInsertURL),
+				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_INSIGHTS_INSERT_URL", nil),
 			},
 			"insights_query_key": {
 				Type:        schema.TypeString,
@@ -87,7 +92,7 @@ func Provider() terraform.ResourceProvider {
 			"insights_query_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_INSIGHTS_QUERY_URL", insightsQueryURL),
+				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_INSIGHTS_QUERY_URL", nil),
 			},
 			"infra_api_url": {
 				Type:        schema.TypeString,
@@ -104,6 +109,114 @@ func Provider() terraform.ResourceProvider {
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_API_CACERT", ""),
 			},
+			"client_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_API_CLIENT_CERT", ""),
+			},
+			"client_key_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_API_CLIENT_KEY", ""),
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_PROXY_URL", ""),
+			},
+			"no_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_NO_PROXY", ""),
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NEWRELIC_MAX_RETRIES", defaultMaxRetries),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"audit_events": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"event_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      defaultAuditEventType,
+							ValidateFunc: validation.NoZeroValues,
+						},
+					},
+				},
+			},
+			"instrumentation": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"license_key": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Sensitive:    true,
+							ValidateFunc: validation.StringLenBetween(40, 40),
+						},
+						"app_name": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      defaultInstrumentationAppName,
+							ValidateFunc: validation.NoZeroValues,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_READ_ONLY", false),
+			},
+			"validate_credentials": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NEWRELIC_VALIDATE_CREDENTIALS", false),
+			},
+			"rate_limits": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"default_tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"retry_max_wait": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("NEWRELIC_RETRY_MAX_WAIT", int(defaultRetryMaxWait.Seconds())),
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
@@ -138,34 +251,105 @@ func Provider() terraform.ResourceProvider {
 		},
 	}
 
+	// Requests and responses may hold the value of any sensitive attribute,
+	// which must be redacted when they are logged.
+	sensitiveFields := sensitiveAPIFields(provider.ResourcesMap, provider.DataSourcesMap)
+
+	for name, r := range provider.ResourcesMap {
+		manageEntityTags(name, r)
+		instrumentOperations(name, r)
+		auditChanges(name, r)
+		protectFromDeletion(name, r)
+		refuseMutationsWhenReadOnly(name, r)
+		explainAPIErrors(name, r)
+	}
+
+	for name, r := range provider.DataSourcesMap {
+		explainAPIErrors(name, r)
+	}
+
 	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
 		terraformVersion := provider.TerraformVersion
 		if terraformVersion == "" {
 			// Catch for versions < 0.12
 			terraformVersion = "0.11+compatible"
 		}
-		return providerConfigure(d, terraformVersion)
+		return providerConfigure(provider.StopContext(), d, terraformVersion, sensitiveFields)
 	}
 
 	return provider
 }
 
-func providerConfigure(data *schema.ResourceData, terraformVersion string) (interface{}, error) {
+func providerConfigure(stopCtx context.Context, data *schema.ResourceData, terraformVersion string, sensitiveFields map[string][]string) (interface{}, error) {
 	adminAPIKey := data.Get("api_key").(string)
 	personalAPIKey := data.Get("personal_api_key").(string)
 	userAgent := fmt.Sprintf("%s %s/%s", httpclient.TerraformUserAgent(terraformVersion), TerraformProviderProductUserAgent, version.ProviderVersion)
 
+	endpoints, err := resolveRegionEndpoints(data.Get("region").(string), map[string]string{
+		"api_url":                data.Get("api_url").(string),
+		"infrastructure_api_url": getInfraAPIURL(data),
+		"insights_insert_url":    data.Get("insights_insert_url").(string),
+		"insights_query_url":     data.Get("insights_query_url").(string),
+		"nerdgraph_api_url":      data.Get("nerdgraph_api_url").(string),
+		"synthetics_api_url":     data.Get("synthetics_api_url").(string),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimits, err := expandRateLimits(data.Get("rate_limits").(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimiter := NewRateLimiter(map[apiFamily]string{
+		apiFamilyREST:           endpoints["api_url"],
+		apiFamilyNerdGraph:      endpoints["nerdgraph_api_url"],
+		apiFamilySynthetics:     endpoints["synthetics_api_url"],
+		apiFamilyInfrastructure: endpoints["infrastructure_api_url"],
+	}, rateLimits)
+
 	cfg := Config{
 		AdminAPIKey:          adminAPIKey,
 		PersonalAPIKey:       personalAPIKey,
-		APIURL:               data.Get("api_url").(string),
-		SyntheticsAPIURL:     data.Get("synthetics_api_url").(string),
-		NerdGraphAPIURL:      data.Get("nerdgraph_api_url").(string),
-		InfrastructureAPIURL: getInfraAPIURL(data),
+		APIURL:               endpoints["api_url"],
+		SyntheticsAPIURL:     endpoints["synthetics_api_url"],
+		NerdGraphAPIURL:      endpoints["nerdgraph_api_url"],
+		InfrastructureAPIURL: endpoints["infrastructure_api_url"],
+		InsightsAccountID:    data.Get("insights_account_id").(string),
+		InsightsInsertKey:    data.Get("insights_insert_key").(string),
+		InsightsInsertURL:    endpoints["insights_insert_url"],
+		InsightsQueryKey:     data.Get("insights_query_key").(string),
+		InsightsQueryURL:     endpoints["insights_query_url"],
 		userAgent:            userAgent,
 		InsecureSkipVerify:   data.Get("insecure_skip_verify").(bool),
 		CACertFile:           data.Get("cacert_file").(string),
+		ClientCertFile:       data.Get("client_cert_file").(string),
+		ClientKeyFile:        data.Get("client_key_file").(string),
+		ProxyURL:             data.Get("proxy_url").(string),
+		NoProxy:              data.Get("no_proxy").(string),
+		MaxRetries:           data.Get("max_retries").(int),
+		RateLimiter:          rateLimiter,
+		RetryMaxWait:         time.Duration(data.Get("retry_max_wait").(int)) * time.Second,
+		SensitiveFields:      sensitiveFields,
+		listCache:            newListCache(),
+	}
+	instrumentation, err := expandInstrumentation(data.Get("instrumentation").([]interface{}), &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing instrumentation: %w", err)
+	}
+
+	if instrumentation != nil {
+		cfg.Instrumented = true
+
+		// Data is otherwise sent when the plugin exits, which it may not
+		// get to do once Terraform is interrupted.
+		go func() {
+			<-stopCtx.Done()
+			instrumentation.shutdown()
+		}()
 	}
+
 	log.Println("[INFO] Initializing newrelic-client-go")
 
 	client, err := cfg.Client()
@@ -173,37 +357,63 @@ func providerConfigure(data *schema.ResourceData, terraformVersion string) (inte
 		return nil, fmt.Errorf("error initializing newrelic-client-go: %w", err)
 	}
 
-	insightsInsertConfig := Config{
-		InsightsAccountID: data.Get("insights_account_id").(string),
-		InsightsInsertKey: data.Get("insights_insert_key").(string),
-		InsightsInsertURL: data.Get("insights_insert_url").(string),
-	}
-	clientInsightsInsert, err := insightsInsertConfig.ClientInsightsInsert()
-	if err != nil {
-		return nil, fmt.Errorf("error initializing New Relic Insights insert client: %w", err)
+	providerConfig := ProviderConfig{
+		NewClient:       client,
+		PersonalAPIKey:  personalAPIKey,
+		AccountID:       data.Get("account_id").(int),
+		RateLimiter:     rateLimiter,
+		ReadOnly:        data.Get("read_only").(bool),
+		clientConfig:    &cfg,
+		stopCtx:         stopCtx,
+		auditEvents:     expandAuditEvents(data.Get("audit_events").([]interface{}), terraformVersion),
+		defaultTags:     expandEntityTags(data.Get("default_tags")),
+		listCache:       cfg.listCache,
+		locks:           mutexkv.NewMutexKV(),
+		instrumentation: instrumentation,
 	}
 
-	insightsQueryConfig := Config{
-		InsightsAccountID: data.Get("insights_account_id").(string),
-		InsightsQueryKey:  data.Get("insights_query_key").(string),
-		InsightsQueryURL:  data.Get("insights_query_url").(string),
+	if providerConfig.auditEvents != nil && (cfg.InsightsAccountID == "" || cfg.InsightsInsertKey == "") {
+		return nil, fmt.Errorf("audit_events requires insights_account_id and insights_insert_key to be set")
 	}
-	clientInsightsQuery, err := insightsQueryConfig.ClientInsightsQuery()
-	if err != nil {
-		return nil, fmt.Errorf("error initializing New Relic Insights query client: %s", err)
+
+	if data.Get("validate_credentials").(bool) {
+		log.Println("[INFO] Validating New Relic credentials")
+
+		if err := providerConfig.validateCredentials(adminAPIKey); err != nil {
+			return nil, err
+		}
 	}
 
-	providerConfig := ProviderConfig{
-		NewClient:            client,
-		InsightsInsertClient: clientInsightsInsert,
-		InsightsQueryClient:  clientInsightsQuery,
-		PersonalAPIKey:       personalAPIKey,
-		AccountID:            data.Get("account_id").(int),
+	if providerConfig.ReadOnly {
+		log.Println("[INFO] Provider is read-only, resources will not be created, updated or deleted")
 	}
 
 	return &providerConfig, nil
 }
 
+// Wraps the Create, Update and Delete functions of a resource so that they fail
+// before making any API call when the provider is read-only. Reads, imports
+// and data sources are left as they are, so plans still show drift.
+func refuseMutationsWhenReadOnly(name string, r *schema.Resource) {
+	guard := func(action string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if f == nil {
+			return nil
+		}
+
+		return func(d *schema.ResourceData, meta interface{}) error {
+			if providerConfig, ok := meta.(*ProviderConfig); ok && providerConfig.ReadOnly {
+				return fmt.Errorf("cannot %s %s: the provider is configured with read_only = true", action, name)
+			}
+
+			return f(d, meta)
+		}
+	}
+
+	r.Create = guard("create", r.Create)
+	r.Update = guard("update", r.Update)
+	r.Delete = guard("delete", r.Delete)
+}
+
 func getInfraAPIURL(data *schema.ResourceData) string {
 	oldURL, oldURLOk := data.GetOk("infra_api_url")
 	newURL, newURLOk := data.GetOk("infrastructure_api_url")
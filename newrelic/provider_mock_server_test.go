@@ -0,0 +1,1745 @@
+package newrelic
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// The mock API server is an in-memory stand-in for the New Relic REST,
+// Synthetics, Infrastructure and NerdGraph APIs. It allows the acceptance
+// suite to run without network access or a live account when the
+// NEWRELIC_MOCK_API environment variable is set.
+const (
+	mockTimestamp      = "2020-01-01T00:00:00.000+0000"
+	mockAPIKey         = "mock-api-key"
+	mockPersonalAPIKey = "mock-personal-api-key"
+	// Matches the account ID hard-coded in the NerdGraph test fixtures.
+	mockAccountID = 2520528
+)
+
+type mockAPIRoute struct {
+	method  string
+	pattern *regexp.Regexp
+	handler func(w http.ResponseWriter, r *http.Request, params []string)
+}
+
+type mockRecord struct {
+	PolicyID int
+	Data     map[string]interface{}
+}
+
+type mockAPIServer struct {
+	sync.Mutex
+
+	server *httptest.Server
+	routes []mockAPIRoute
+	nextID int
+
+	policies        map[int]map[string]interface{}
+	channels        map[int]map[string]interface{}
+	policyChannels  map[int]map[int]bool
+	conditions      map[int]*mockRecord
+	synthConditions map[int]*mockRecord
+	nrqlConditions  map[int]*mockRecord
+	infraConditions map[int]*mockRecord
+	dashboards      map[int]map[string]interface{}
+	monitors        map[string]map[string]interface{}
+	monitorScripts  map[string]map[string]interface{}
+	monitorLabels   map[string]map[string]bool
+	credentials     map[string]map[string]interface{}
+	workloads       map[string]map[string]interface{}
+	applications    map[int]map[string]interface{}
+	entityTags      map[string]map[string][]string
+}
+
+func newMockAPIServer() *mockAPIServer {
+	m := &mockAPIServer{
+		nextID:          1000,
+		policies:        map[int]map[string]interface{}{},
+		channels:        map[int]map[string]interface{}{},
+		policyChannels:  map[int]map[int]bool{},
+		conditions:      map[int]*mockRecord{},
+		synthConditions: map[int]*mockRecord{},
+		nrqlConditions:  map[int]*mockRecord{},
+		infraConditions: map[int]*mockRecord{},
+		dashboards:      map[int]map[string]interface{}{},
+		monitors:        map[string]map[string]interface{}{},
+		monitorScripts:  map[string]map[string]interface{}{},
+		monitorLabels:   map[string]map[string]bool{},
+		credentials:     map[string]map[string]interface{}{},
+		workloads:       map[string]map[string]interface{}{},
+		applications:    map[int]map[string]interface{}{},
+		entityTags:      map[string]map[string][]string{},
+	}
+
+	m.routes = []mockAPIRoute{
+		// REST API (v2)
+		{"GET", regexp.MustCompile(`^/v2/alerts_policies\.json$`), m.listPolicies},
+		{"POST", regexp.MustCompile(`^/v2/alerts_policies\.json$`), m.createPolicy},
+		{"PUT", regexp.MustCompile(`^/v2/alerts_policies/(\d+)\.json$`), m.updatePolicy},
+		{"DELETE", regexp.MustCompile(`^/v2/alerts_policies/(\d+)\.json$`), m.deletePolicy},
+		{"GET", regexp.MustCompile(`^/v2/alerts_channels\.json$`), m.listChannels},
+		{"POST", regexp.MustCompile(`^/v2/alerts_channels\.json$`), m.createChannel},
+		{"DELETE", regexp.MustCompile(`^/v2/alerts_channels/(\d+)\.json$`), m.deleteChannel},
+		{"PUT", regexp.MustCompile(`^/v2/alerts_policy_channels\.json$`), m.updatePolicyChannels},
+		{"DELETE", regexp.MustCompile(`^/v2/alerts_policy_channels\.json$`), m.deletePolicyChannel},
+		{"GET", regexp.MustCompile(`^/v2/alerts_conditions\.json$`), m.listRecords(m.conditions, "conditions")},
+		{"POST", regexp.MustCompile(`^/v2/alerts_conditions/policies/(\d+)\.json$`), m.createRecord(m.conditions, "condition")},
+		{"PUT", regexp.MustCompile(`^/v2/alerts_conditions/(\d+)\.json$`), m.updateRecord(m.conditions, "condition")},
+		{"DELETE", regexp.MustCompile(`^/v2/alerts_conditions/(\d+)\.json$`), m.deleteRecord(m.conditions, "condition")},
+		{"GET", regexp.MustCompile(`^/v2/alerts_synthetics_conditions\.json$`), m.listRecords(m.synthConditions, "synthetics_conditions")},
+		{"POST", regexp.MustCompile(`^/v2/alerts_synthetics_conditions/policies/(\d+)\.json$`), m.createRecord(m.synthConditions, "synthetics_condition")},
+		{"PUT", regexp.MustCompile(`^/v2/alerts_synthetics_conditions/(\d+)\.json$`), m.updateRecord(m.synthConditions, "synthetics_condition")},
+		{"DELETE", regexp.MustCompile(`^/v2/alerts_synthetics_conditions/(\d+)\.json$`), m.deleteRecord(m.synthConditions, "synthetics_condition")},
+		{"GET", regexp.MustCompile(`^/v2/alerts_nrql_conditions\.json$`), m.listNrqlConditions},
+		{"POST", regexp.MustCompile(`^/v2/alerts_nrql_conditions/policies/(\d+)\.json$`), m.createNrqlCondition},
+		{"PUT", regexp.MustCompile(`^/v2/alerts_nrql_conditions/(\d+)\.json$`), m.updateNrqlCondition},
+		{"DELETE", regexp.MustCompile(`^/v2/alerts_nrql_conditions/(\d+)\.json$`), m.deleteNrqlCondition},
+		{"GET", regexp.MustCompile(`^/v2/dashboards\.json$`), m.listDashboards},
+		{"POST", regexp.MustCompile(`^/v2/dashboards\.json$`), m.createDashboard},
+		{"GET", regexp.MustCompile(`^/v2/dashboards/(\d+)\.json$`), m.getDashboard},
+		{"PUT", regexp.MustCompile(`^/v2/dashboards/(\d+)\.json$`), m.updateDashboard},
+		{"DELETE", regexp.MustCompile(`^/v2/dashboards/(\d+)\.json$`), m.deleteDashboard},
+		{"GET", regexp.MustCompile(`^/v2/applications\.json$`), m.listApplications},
+		{"GET", regexp.MustCompile(`^/v2/applications/(\d+)\.json$`), m.getApplication},
+		{"PUT", regexp.MustCompile(`^/v2/applications/(\d+)\.json$`), m.updateApplication},
+
+		// Infrastructure API
+		{"GET", regexp.MustCompile(`^/infra/v2/alerts/conditions$`), m.listRecords(m.infraConditions, "data")},
+		{"POST", regexp.MustCompile(`^/infra/v2/alerts/conditions$`), m.createRecord(m.infraConditions, "data")},
+		{"GET", regexp.MustCompile(`^/infra/v2/alerts/conditions/(\d+)$`), m.getRecord(m.infraConditions, "data")},
+		{"PUT", regexp.MustCompile(`^/infra/v2/alerts/conditions/(\d+)$`), m.updateRecord(m.infraConditions, "data")},
+		{"DELETE", regexp.MustCompile(`^/infra/v2/alerts/conditions/(\d+)$`), m.deleteRecord(m.infraConditions, "")},
+
+		// Synthetics API
+		{"GET", regexp.MustCompile(`^/synthetics/api/v4/monitors$`), m.listMonitors},
+		{"POST", regexp.MustCompile(`^/synthetics/api/v4/monitors$`), m.createMonitor},
+		{"GET", regexp.MustCompile(`^/synthetics/api/v4/monitors/([\w-]+)$`), m.getMonitor},
+		{"PUT", regexp.MustCompile(`^/synthetics/api/v4/monitors/([\w-]+)$`), m.updateMonitor},
+		{"DELETE", regexp.MustCompile(`^/synthetics/api/v4/monitors/([\w-]+)$`), m.deleteMonitor},
+		{"GET", regexp.MustCompile(`^/synthetics/api/v4/monitors/([\w-]+)/script$`), m.getMonitorScript},
+		{"PUT", regexp.MustCompile(`^/synthetics/api/v4/monitors/([\w-]+)/script$`), m.updateMonitorScript},
+		{"GET", regexp.MustCompile(`^/synthetics/api/v4/monitors/([\w-]+)/labels$`), m.getMonitorLabels},
+		{"POST", regexp.MustCompile(`^/synthetics/api/v4/monitors/([\w-]+)/labels$`), m.addMonitorLabel},
+		{"DELETE", regexp.MustCompile(`^/synthetics/api/v4/monitors/([\w-]+)/labels/([^/]+)$`), m.deleteMonitorLabel},
+		{"GET", regexp.MustCompile(`^/synthetics/api/v1/secure-credentials$`), m.listSecureCredentials},
+		{"POST", regexp.MustCompile(`^/synthetics/api/v1/secure-credentials$`), m.createSecureCredential},
+		{"GET", regexp.MustCompile(`^/synthetics/api/v1/secure-credentials/([^/]+)$`), m.getSecureCredential},
+		{"PUT", regexp.MustCompile(`^/synthetics/api/v1/secure-credentials/([^/]+)$`), m.updateSecureCredential},
+		{"DELETE", regexp.MustCompile(`^/synthetics/api/v1/secure-credentials/([^/]+)$`), m.deleteSecureCredential},
+
+		// NerdGraph
+		{"POST", regexp.MustCompile(`^/graphql$`), m.nerdGraph},
+	}
+
+	m.server = httptest.NewServer(m)
+
+	return m
+}
+
+func (m *mockAPIServer) URL() string {
+	return m.server.URL
+}
+
+func (m *mockAPIServer) Close() {
+	m.server.Close()
+}
+
+func (m *mockAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !mockAuthorized(r) {
+		m.writeError(w, http.StatusUnauthorized, "Invalid credentials")
+		return
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	for _, route := range m.routes {
+		if route.method != r.Method {
+			continue
+		}
+
+		if params := route.pattern.FindStringSubmatch(r.URL.Path); params != nil {
+			route.handler(w, r, params[1:])
+			return
+		}
+	}
+
+	log.Printf("[WARN] mock API server: unsupported endpoint %s %s", r.Method, r.URL.Path)
+	m.writeError(w, http.StatusNotImplemented, fmt.Sprintf("mock API server does not implement %s %s", r.Method, r.URL.Path))
+}
+
+// REST requests are authorized with the mock admin API key,
+// and NerdGraph requests with the mock personal API key.
+func mockAuthorized(r *http.Request) bool {
+	if key := r.Header.Get("X-Api-Key"); key != "" {
+		return key == mockAPIKey
+	}
+
+	return r.Header.Get("Api-Key") == mockPersonalAPIKey
+}
+
+// config returns a provider configuration pointing at the mock API server.
+func (m *mockAPIServer) config() Config {
+	return Config{
+		AdminAPIKey:          mockAPIKey,
+		PersonalAPIKey:       mockPersonalAPIKey,
+		APIURL:               m.URL() + "/v2",
+		SyntheticsAPIURL:     m.URL() + "/synthetics/api",
+		InfrastructureAPIURL: m.URL() + "/infra/v2",
+		NerdGraphAPIURL:      m.URL() + "/graphql",
+		userAgent:            "terraform-provider-newrelic-test",
+	}
+}
+
+func (m *mockAPIServer) newID() int {
+	m.nextID++
+	return m.nextID
+}
+
+func (m *mockAPIServer) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+func (m *mockAPIServer) writeError(w http.ResponseWriter, status int, message string) {
+	m.writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"title": message,
+		},
+	})
+}
+
+func (m *mockAPIServer) readBody(r *http.Request, key string) (map[string]interface{}, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := map[string]interface{}{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, err
+	}
+
+	if key == "" {
+		return decoded, nil
+	}
+
+	data, ok := decoded[key].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("request body is missing the %q object", key)
+	}
+
+	return data, nil
+}
+
+func mockAtoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
+
+func mockSortedIDs(ids map[int]bool) []int {
+	sorted := []int{}
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+
+	sort.Ints(sorted)
+
+	return sorted
+}
+
+// Alert policies
+
+func (m *mockAPIServer) listPolicies(w http.ResponseWriter, r *http.Request, params []string) {
+	name := r.URL.Query().Get("filter[name]")
+	policies := []interface{}{}
+
+	for _, id := range m.sortedPolicyIDs() {
+		policy := m.policies[id]
+		if name == "" || strings.Contains(policy["name"].(string), name) {
+			policies = append(policies, policy)
+		}
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"policies": policies})
+}
+
+func (m *mockAPIServer) sortedPolicyIDs() []int {
+	ids := map[int]bool{}
+	for id := range m.policies {
+		ids[id] = true
+	}
+
+	return mockSortedIDs(ids)
+}
+
+func (m *mockAPIServer) createPolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	policy, err := m.readBody(r, "policy")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := m.newID()
+	policy["id"] = id
+
+	if policy["incident_preference"] == nil {
+		policy["incident_preference"] = "PER_POLICY"
+	}
+
+	m.policies[id] = policy
+
+	m.writeJSON(w, http.StatusCreated, map[string]interface{}{"policy": policy})
+}
+
+func (m *mockAPIServer) updatePolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	id := mockAtoi(params[0])
+	if _, ok := m.policies[id]; !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	policy, err := m.readBody(r, "policy")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for k, v := range policy {
+		m.policies[id][k] = v
+	}
+
+	m.policies[id]["id"] = id
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"policy": m.policies[id]})
+}
+
+func (m *mockAPIServer) deletePolicy(w http.ResponseWriter, r *http.Request, params []string) {
+	id := mockAtoi(params[0])
+
+	policy, ok := m.policies[id]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	m.removePolicy(id)
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"policy": policy})
+}
+
+// Deleting a policy also deletes its conditions and channel associations.
+func (m *mockAPIServer) removePolicy(id int) {
+	delete(m.policies, id)
+	delete(m.policyChannels, id)
+
+	for _, records := range []map[int]*mockRecord{m.conditions, m.synthConditions, m.nrqlConditions, m.infraConditions} {
+		for conditionID, record := range records {
+			if record.PolicyID == id {
+				delete(records, conditionID)
+			}
+		}
+	}
+}
+
+// Alert channels
+
+// Like the real API, secret channel configuration values are write-only.
+var mockChannelSecrets = []string{"api_key", "auth_password", "key", "service_key", "url"}
+
+func (m *mockAPIServer) channelWithLinks(id int) map[string]interface{} {
+	channel := map[string]interface{}{}
+	for k, v := range m.channels[id] {
+		channel[k] = v
+	}
+
+	if cfg, ok := channel["configuration"].(map[string]interface{}); ok {
+		redacted := map[string]interface{}{}
+		for k, v := range cfg {
+			redacted[k] = v
+		}
+
+		for _, k := range mockChannelSecrets {
+			delete(redacted, k)
+		}
+
+		channel["configuration"] = redacted
+	}
+
+	policyIDs := map[int]bool{}
+	for policyID, channelIDs := range m.policyChannels {
+		if channelIDs[id] {
+			policyIDs[policyID] = true
+		}
+	}
+
+	channel["links"] = map[string]interface{}{"policy_ids": mockSortedIDs(policyIDs)}
+
+	return channel
+}
+
+func (m *mockAPIServer) listChannels(w http.ResponseWriter, r *http.Request, params []string) {
+	ids := map[int]bool{}
+	for id := range m.channels {
+		ids[id] = true
+	}
+
+	channels := []interface{}{}
+	for _, id := range mockSortedIDs(ids) {
+		channels = append(channels, m.channelWithLinks(id))
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"channels": channels})
+}
+
+func (m *mockAPIServer) createChannel(w http.ResponseWriter, r *http.Request, params []string) {
+	channel, err := m.readBody(r, "channel")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := m.newID()
+	channel["id"] = id
+	m.channels[id] = channel
+
+	m.writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"channels": []interface{}{m.channelWithLinks(id)},
+	})
+}
+
+func (m *mockAPIServer) deleteChannel(w http.ResponseWriter, r *http.Request, params []string) {
+	id := mockAtoi(params[0])
+	if _, ok := m.channels[id]; !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	channel := m.channelWithLinks(id)
+
+	delete(m.channels, id)
+	for _, channelIDs := range m.policyChannels {
+		delete(channelIDs, id)
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"channel": channel})
+}
+
+func (m *mockAPIServer) updatePolicyChannels(w http.ResponseWriter, r *http.Request, params []string) {
+	policyID := mockAtoi(r.URL.Query().Get("policy_id"))
+	if _, ok := m.policies[policyID]; !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	if m.policyChannels[policyID] == nil {
+		m.policyChannels[policyID] = map[int]bool{}
+	}
+
+	for _, raw := range strings.Split(r.URL.Query().Get("channel_ids"), ",") {
+		channelID := mockAtoi(raw)
+		if _, ok := m.channels[channelID]; !ok {
+			m.writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("channel %s does not exist", raw))
+			return
+		}
+
+		m.policyChannels[policyID][channelID] = true
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"policy": map[string]interface{}{
+			"id":          policyID,
+			"channel_ids": mockSortedIDs(m.policyChannels[policyID]),
+		},
+	})
+}
+
+func (m *mockAPIServer) deletePolicyChannel(w http.ResponseWriter, r *http.Request, params []string) {
+	policyID := mockAtoi(r.URL.Query().Get("policy_id"))
+	channelID := mockAtoi(r.URL.Query().Get("channel_id"))
+
+	if _, ok := m.channels[channelID]; !ok || !m.policyChannels[policyID][channelID] {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	delete(m.policyChannels[policyID], channelID)
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"channel": m.channelWithLinks(channelID)})
+}
+
+// Policy-scoped records (APM and Infrastructure conditions)
+
+func (m *mockAPIServer) listRecords(records map[int]*mockRecord, key string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		policyID := mockAtoi(r.URL.Query().Get("policy_id"))
+
+		ids := map[int]bool{}
+		for id, record := range records {
+			if policyID == 0 || record.PolicyID == policyID {
+				ids[id] = true
+			}
+		}
+
+		list := []interface{}{}
+		for _, id := range mockSortedIDs(ids) {
+			list = append(list, records[id].Data)
+		}
+
+		m.writeJSON(w, http.StatusOK, map[string]interface{}{key: list})
+	}
+}
+
+func (m *mockAPIServer) getRecord(records map[int]*mockRecord, key string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		record, ok := records[mockAtoi(params[0])]
+		if !ok {
+			m.writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+
+		m.writeJSON(w, http.StatusOK, map[string]interface{}{key: record.Data})
+	}
+}
+
+// The policy ID is taken from the URL for REST conditions,
+// and from the request body for Infrastructure conditions.
+func (m *mockAPIServer) createRecord(records map[int]*mockRecord, key string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		data, err := m.readBody(r, key)
+		if err != nil {
+			m.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var policyID int
+		if len(params) > 0 {
+			policyID = mockAtoi(params[0])
+		} else if v, ok := data["policy_id"].(float64); ok {
+			policyID = int(v)
+		}
+
+		if _, ok := m.policies[policyID]; !ok {
+			m.writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+
+		id := m.newID()
+		data["id"] = id
+		records[id] = &mockRecord{PolicyID: policyID, Data: data}
+
+		m.writeJSON(w, http.StatusCreated, map[string]interface{}{key: data})
+	}
+}
+
+func (m *mockAPIServer) updateRecord(records map[int]*mockRecord, key string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		id := mockAtoi(params[0])
+
+		record, ok := records[id]
+		if !ok {
+			m.writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+
+		data, err := m.readBody(r, key)
+		if err != nil {
+			m.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		data["id"] = id
+		record.Data = data
+
+		m.writeJSON(w, http.StatusOK, map[string]interface{}{key: data})
+	}
+}
+
+func (m *mockAPIServer) deleteRecord(records map[int]*mockRecord, key string) func(http.ResponseWriter, *http.Request, []string) {
+	return func(w http.ResponseWriter, r *http.Request, params []string) {
+		id := mockAtoi(params[0])
+
+		record, ok := records[id]
+		if !ok {
+			m.writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+
+		delete(records, id)
+
+		if key == "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		m.writeJSON(w, http.StatusOK, map[string]interface{}{key: record.Data})
+	}
+}
+
+// NRQL conditions are stored in their NerdGraph shape and converted
+// when they are accessed through the REST API.
+
+func (m *mockAPIServer) listNrqlConditions(w http.ResponseWriter, r *http.Request, params []string) {
+	policyID := mockAtoi(r.URL.Query().Get("policy_id"))
+
+	ids := map[int]bool{}
+	for id, record := range m.nrqlConditions {
+		if policyID == 0 || record.PolicyID == policyID {
+			ids[id] = true
+		}
+	}
+
+	list := []interface{}{}
+	for _, id := range mockSortedIDs(ids) {
+		list = append(list, mockNrqlConditionToREST(m.nrqlConditions[id].Data))
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"nrql_conditions": list})
+}
+
+func (m *mockAPIServer) createNrqlCondition(w http.ResponseWriter, r *http.Request, params []string) {
+	policyID := mockAtoi(params[0])
+	if _, ok := m.policies[policyID]; !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	data, err := m.readBody(r, "nrql_condition")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := m.newID()
+	condition := mockNrqlConditionFromREST(data)
+	condition["id"] = strconv.Itoa(id)
+	condition["policyId"] = strconv.Itoa(policyID)
+	m.nrqlConditions[id] = &mockRecord{PolicyID: policyID, Data: condition}
+
+	m.writeJSON(w, http.StatusCreated, map[string]interface{}{"nrql_condition": mockNrqlConditionToREST(condition)})
+}
+
+func (m *mockAPIServer) updateNrqlCondition(w http.ResponseWriter, r *http.Request, params []string) {
+	id := mockAtoi(params[0])
+
+	record, ok := m.nrqlConditions[id]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	data, err := m.readBody(r, "nrql_condition")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	condition := mockNrqlConditionFromREST(data)
+	condition["id"] = strconv.Itoa(id)
+	condition["policyId"] = strconv.Itoa(record.PolicyID)
+	record.Data = condition
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"nrql_condition": mockNrqlConditionToREST(condition)})
+}
+
+func (m *mockAPIServer) deleteNrqlCondition(w http.ResponseWriter, r *http.Request, params []string) {
+	id := mockAtoi(params[0])
+
+	record, ok := m.nrqlConditions[id]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	delete(m.nrqlConditions, id)
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"nrql_condition": mockNrqlConditionToREST(record.Data)})
+}
+
+func mockNrqlConditionFromREST(data map[string]interface{}) map[string]interface{} {
+	condition := map[string]interface{}{
+		"name":           data["name"],
+		"enabled":        data["enabled"],
+		"runbookUrl":     data["runbook_url"],
+		"type":           strings.ToUpper(fmt.Sprint(data["type"])),
+		"valueFunction":  strings.ToUpper(fmt.Sprint(data["value_function"])),
+		"expectedGroups": data["expected_groups"],
+
+		// REST-only attributes
+		"violationTimeLimitSeconds": data["violation_time_limit_seconds"],
+	}
+
+	if condition["type"] == "OUTLIER" {
+		ignoreOverlap, _ := data["ignore_overlap"].(bool)
+		condition["openViolationOnGroupOverlap"] = !ignoreOverlap
+	}
+
+	nrql, _ := data["nrql"].(map[string]interface{})
+	sinceValue, _ := nrql["since_value"].(string)
+	if sinceValue == "" {
+		// As the REST API does.
+		sinceValue = "3"
+	}
+	condition["nrql"] = map[string]interface{}{
+		"query":            nrql["query"],
+		"evaluationOffset": mockAtoi(sinceValue),
+	}
+
+	terms := []interface{}{}
+	rawTerms, _ := data["terms"].([]interface{})
+	for _, t := range rawTerms {
+		term := t.(map[string]interface{})
+		threshold, _ := strconv.ParseFloat(fmt.Sprint(term["threshold"]), 64)
+
+		occurrences := "ALL"
+		if term["time_function"] == "any" {
+			occurrences = "AT_LEAST_ONCE"
+		}
+
+		terms = append(terms, map[string]interface{}{
+			"operator":             strings.ToUpper(fmt.Sprint(term["operator"])),
+			"priority":             strings.ToUpper(fmt.Sprint(term["priority"])),
+			"threshold":            threshold,
+			"thresholdDuration":    mockAtoi(fmt.Sprint(term["duration"])) * 60,
+			"thresholdOccurrences": occurrences,
+		})
+	}
+
+	condition["terms"] = terms
+
+	return condition
+}
+
+func mockNrqlConditionToREST(condition map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{
+		"id":                           mockAtoi(fmt.Sprint(condition["id"])),
+		"name":                         condition["name"],
+		"enabled":                      condition["enabled"],
+		"runbook_url":                  condition["runbookUrl"],
+		"type":                         strings.ToLower(fmt.Sprint(condition["type"])),
+		"expected_groups":              condition["expectedGroups"],
+		"violation_time_limit_seconds": condition["violationTimeLimitSeconds"],
+	}
+
+	if overlap, ok := condition["openViolationOnGroupOverlap"].(bool); ok {
+		data["ignore_overlap"] = !overlap
+	}
+
+	if valueFunction, ok := condition["valueFunction"].(string); ok {
+		data["value_function"] = strings.ToLower(valueFunction)
+	}
+
+	nrql, _ := condition["nrql"].(map[string]interface{})
+	data["nrql"] = map[string]interface{}{
+		"query":       nrql["query"],
+		"since_value": fmt.Sprint(nrql["evaluationOffset"]),
+	}
+
+	terms := []interface{}{}
+	rawTerms, _ := condition["terms"].([]interface{})
+	for _, t := range rawTerms {
+		term := t.(map[string]interface{})
+
+		timeFunction := "all"
+		if term["thresholdOccurrences"] == "AT_LEAST_ONCE" {
+			timeFunction = "any"
+		}
+
+		terms = append(terms, map[string]interface{}{
+			"operator":      strings.ToLower(fmt.Sprint(term["operator"])),
+			"priority":      strings.ToLower(fmt.Sprint(term["priority"])),
+			"threshold":     fmt.Sprint(term["threshold"]),
+			"duration":      strconv.Itoa(mockAtoi(fmt.Sprint(term["thresholdDuration"])) / 60),
+			"time_function": timeFunction,
+		})
+	}
+
+	data["terms"] = terms
+
+	return data
+}
+
+// Dashboards
+
+func (m *mockAPIServer) listDashboards(w http.ResponseWriter, r *http.Request, params []string) {
+	title := r.URL.Query().Get("filter[title]")
+
+	ids := map[int]bool{}
+	for id := range m.dashboards {
+		ids[id] = true
+	}
+
+	dashboards := []interface{}{}
+	for _, id := range mockSortedIDs(ids) {
+		if title == "" || strings.Contains(m.dashboards[id]["title"].(string), title) {
+			dashboards = append(dashboards, m.dashboards[id])
+		}
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"dashboards": dashboards})
+}
+
+func (m *mockAPIServer) getDashboard(w http.ResponseWriter, r *http.Request, params []string) {
+	dashboard, ok := m.dashboards[mockAtoi(params[0])]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"dashboard": dashboard})
+}
+
+func (m *mockAPIServer) createDashboard(w http.ResponseWriter, r *http.Request, params []string) {
+	dashboard, err := m.readBody(r, "dashboard")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := m.newID()
+	m.saveDashboard(id, dashboard)
+
+	m.writeJSON(w, http.StatusCreated, map[string]interface{}{"dashboard": dashboard})
+}
+
+func (m *mockAPIServer) updateDashboard(w http.ResponseWriter, r *http.Request, params []string) {
+	id := mockAtoi(params[0])
+	if _, ok := m.dashboards[id]; !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	dashboard, err := m.readBody(r, "dashboard")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	m.saveDashboard(id, dashboard)
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"dashboard": dashboard})
+}
+
+func (m *mockAPIServer) saveDashboard(id int, dashboard map[string]interface{}) {
+	dashboard["id"] = id
+	dashboard["ui_url"] = fmt.Sprintf("%s/dashboards/%d", m.URL(), id)
+	dashboard["api_url"] = fmt.Sprintf("%s/v2/dashboards/%d.json", m.URL(), id)
+
+	if dashboard["metadata"] == nil {
+		dashboard["metadata"] = map[string]interface{}{"version": 1}
+	}
+
+	widgets, _ := dashboard["widgets"].([]interface{})
+	for _, w := range widgets {
+		widget := w.(map[string]interface{})
+		if v, ok := widget["widget_id"].(float64); !ok || v == 0 {
+			widget["widget_id"] = m.newID()
+		}
+	}
+
+	m.dashboards[id] = dashboard
+}
+
+func (m *mockAPIServer) deleteDashboard(w http.ResponseWriter, r *http.Request, params []string) {
+	id := mockAtoi(params[0])
+
+	dashboard, ok := m.dashboards[id]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	delete(m.dashboards, id)
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"dashboard": dashboard})
+}
+
+// APM applications cannot be created through the API, see reportApplication.
+
+// reportApplication stands in for an APM agent reporting data under the given
+// application name, which is how the acceptance tests create applications.
+func (m *mockAPIServer) reportApplication(name string) {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, app := range m.applications {
+		if app["name"] == name {
+			return
+		}
+	}
+
+	id := m.newID()
+	m.applications[id] = map[string]interface{}{
+		"id":        id,
+		"name":      name,
+		"language":  "go",
+		"reporting": true,
+		"settings": map[string]interface{}{
+			"app_apdex_threshold":         0.5,
+			"end_user_apdex_threshold":    7.0,
+			"enable_real_user_monitoring": true,
+		},
+	}
+}
+
+func (m *mockAPIServer) listApplications(w http.ResponseWriter, r *http.Request, params []string) {
+	name := r.URL.Query().Get("filter[name]")
+
+	ids := map[int]bool{}
+	for id := range m.applications {
+		ids[id] = true
+	}
+
+	applications := []interface{}{}
+	for _, id := range mockSortedIDs(ids) {
+		if name == "" || strings.Contains(m.applications[id]["name"].(string), name) {
+			applications = append(applications, m.applications[id])
+		}
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"applications": applications})
+}
+
+func (m *mockAPIServer) getApplication(w http.ResponseWriter, r *http.Request, params []string) {
+	application, ok := m.applications[mockAtoi(params[0])]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"application": application})
+}
+
+func (m *mockAPIServer) updateApplication(w http.ResponseWriter, r *http.Request, params []string) {
+	application, ok := m.applications[mockAtoi(params[0])]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	data, err := m.readBody(r, "application")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if name, ok := data["name"].(string); ok && name != "" {
+		application["name"] = name
+	}
+
+	if settings, ok := data["settings"].(map[string]interface{}); ok {
+		application["settings"] = settings
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"application": application})
+}
+
+// Synthetics monitors
+
+func (m *mockAPIServer) listMonitors(w http.ResponseWriter, r *http.Request, params []string) {
+	ids := []string{}
+	for id := range m.monitors {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	monitors := []interface{}{}
+	for _, id := range ids {
+		monitors = append(monitors, m.monitors[id])
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"monitors": monitors, "count": len(monitors)})
+}
+
+func (m *mockAPIServer) getMonitor(w http.ResponseWriter, r *http.Request, params []string) {
+	monitor, ok := m.monitors[params[0]]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	m.writeJSON(w, http.StatusOK, monitor)
+}
+
+func (m *mockAPIServer) createMonitor(w http.ResponseWriter, r *http.Request, params []string) {
+	monitor, err := m.readBody(r, "")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := fmt.Sprintf("00000000-0000-0000-0000-%012d", m.newID())
+	monitor["id"] = id
+	m.monitors[id] = monitor
+
+	w.Header().Set("Location", fmt.Sprintf("%s/synthetics/api/v4/monitors/%s", m.URL(), id))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (m *mockAPIServer) updateMonitor(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := m.monitors[params[0]]; !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	monitor, err := m.readBody(r, "")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	monitor["id"] = params[0]
+	m.monitors[params[0]] = monitor
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockAPIServer) deleteMonitor(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := m.monitors[params[0]]; !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	delete(m.monitors, params[0])
+	delete(m.monitorScripts, params[0])
+	delete(m.monitorLabels, params[0])
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockAPIServer) getMonitorScript(w http.ResponseWriter, r *http.Request, params []string) {
+	script, ok := m.monitorScripts[params[0]]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	m.writeJSON(w, http.StatusOK, script)
+}
+
+func (m *mockAPIServer) updateMonitorScript(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := m.monitors[params[0]]; !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	script, err := m.readBody(r, "")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	m.monitorScripts[params[0]] = script
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Monitor labels are posted as a bare "Key:Value" string. Listing
+// the labels of an unknown monitor returns an empty list.
+func (m *mockAPIServer) getMonitorLabels(w http.ResponseWriter, r *http.Request, params []string) {
+	names := []string{}
+	for label := range m.monitorLabels[params[0]] {
+		names = append(names, label)
+	}
+
+	sort.Strings(names)
+
+	labels := []interface{}{}
+	for _, label := range names {
+		parts := strings.SplitN(label, ":", 2)
+		labels = append(labels, map[string]interface{}{
+			"type":  parts[0],
+			"value": parts[1],
+			"href":  fmt.Sprintf("%s/synthetics/api/v4/monitors/%s/labels/%s", m.URL(), params[0], label),
+		})
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"labels": labels, "count": len(labels)})
+}
+
+func (m *mockAPIServer) addMonitorLabel(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := m.monitors[params[0]]; !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || !strings.Contains(string(body), ":") {
+		m.writeError(w, http.StatusBadRequest, "label must be in the form Key:Value")
+		return
+	}
+
+	if m.monitorLabels[params[0]] == nil {
+		m.monitorLabels[params[0]] = map[string]bool{}
+	}
+
+	m.monitorLabels[params[0]][strings.Trim(string(body), `"`)] = true
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockAPIServer) deleteMonitorLabel(w http.ResponseWriter, r *http.Request, params []string) {
+	if !m.monitorLabels[params[0]][params[1]] {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	delete(m.monitorLabels[params[0]], params[1])
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Secure credentials, like the real API, never return their value.
+
+func mockSecureCredential(credential map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"key":         credential["key"],
+		"description": credential["description"],
+		"createdAt":   credential["createdAt"],
+		"lastUpdated": credential["lastUpdated"],
+	}
+}
+
+func (m *mockAPIServer) listSecureCredentials(w http.ResponseWriter, r *http.Request, params []string) {
+	keys := []string{}
+	for key := range m.credentials {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	credentials := []interface{}{}
+	for _, key := range keys {
+		credentials = append(credentials, mockSecureCredential(m.credentials[key]))
+	}
+
+	m.writeJSON(w, http.StatusOK, map[string]interface{}{"secureCredentials": credentials, "count": len(credentials)})
+}
+
+func (m *mockAPIServer) getSecureCredential(w http.ResponseWriter, r *http.Request, params []string) {
+	credential, ok := m.credentials[params[0]]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	m.writeJSON(w, http.StatusOK, mockSecureCredential(credential))
+}
+
+func (m *mockAPIServer) createSecureCredential(w http.ResponseWriter, r *http.Request, params []string) {
+	credential, err := m.readBody(r, "")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key := strings.ToUpper(fmt.Sprint(credential["key"]))
+	if _, ok := m.credentials[key]; ok {
+		m.writeError(w, http.StatusConflict, fmt.Sprintf("secure credential %s already exists", key))
+		return
+	}
+
+	credential["key"] = key
+	credential["createdAt"] = mockTimestamp
+	credential["lastUpdated"] = mockTimestamp
+	m.credentials[key] = credential
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (m *mockAPIServer) updateSecureCredential(w http.ResponseWriter, r *http.Request, params []string) {
+	existing, ok := m.credentials[params[0]]
+	if !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	credential, err := m.readBody(r, "")
+	if err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	credential["key"] = params[0]
+	credential["createdAt"] = existing["createdAt"]
+	credential["lastUpdated"] = mockTimestamp
+	m.credentials[params[0]] = credential
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockAPIServer) deleteSecureCredential(w http.ResponseWriter, r *http.Request, params []string) {
+	if _, ok := m.credentials[params[0]]; !ok {
+		m.writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	delete(m.credentials, params[0])
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// NerdGraph operations are matched on the root field of the query,
+// which is enough to serve the queries issued by newrelic-client-go.
+
+type mockNerdGraphRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+var mockNerdGraphNotFound = []interface{}{
+	map[string]interface{}{
+		"message":    "Not Found",
+		"extensions": map[string]interface{}{"errorClass": "NOT_FOUND"},
+	},
+}
+
+func (m *mockAPIServer) nerdGraph(w http.ResponseWriter, r *http.Request, params []string) {
+	req := mockNerdGraphRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		m.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	vars := req.Variables
+	query := req.Query
+
+	var data interface{}
+	var errs []interface{}
+
+	switch {
+	case strings.Contains(query, "alertsPolicyCreate("):
+		data, errs = m.nerdGraphPolicyCreate(vars)
+	case strings.Contains(query, "alertsPolicyUpdate("):
+		data, errs = m.nerdGraphPolicyUpdate(vars)
+	case strings.Contains(query, "alertsPolicyDelete("):
+		data, errs = m.nerdGraphPolicyDelete(vars)
+	case strings.Contains(query, "policiesSearch("):
+		data, errs = m.nerdGraphPolicySearch(vars)
+	case strings.Contains(query, "policy(id:"):
+		data, errs = m.nerdGraphPolicy(vars)
+	case strings.Contains(query, "alertsNrqlConditionStaticCreate("):
+		data, errs = m.nerdGraphNrqlConditionCreate(vars, mockNerdGraphAlias(query, "alertsNrqlConditionStaticCreate"), "STATIC")
+	case strings.Contains(query, "alertsNrqlConditionBaselineCreate("):
+		data, errs = m.nerdGraphNrqlConditionCreate(vars, mockNerdGraphAlias(query, "alertsNrqlConditionBaselineCreate"), "BASELINE")
+	case strings.Contains(query, "alertsNrqlConditionOutlierCreate("):
+		data, errs = m.nerdGraphNrqlConditionCreate(vars, mockNerdGraphAlias(query, "alertsNrqlConditionOutlierCreate"), "OUTLIER")
+	case strings.Contains(query, "alertsNrqlConditionOutlierUpdate("):
+		data, errs = m.nerdGraphNrqlConditionUpdate(vars, mockNerdGraphAlias(query, "alertsNrqlConditionOutlierUpdate"), "OUTLIER")
+	case strings.Contains(query, "alertsNrqlConditionStaticUpdate("):
+		data, errs = m.nerdGraphNrqlConditionUpdate(vars, mockNerdGraphAlias(query, "alertsNrqlConditionStaticUpdate"), "STATIC")
+	case strings.Contains(query, "alertsNrqlConditionBaselineUpdate("):
+		data, errs = m.nerdGraphNrqlConditionUpdate(vars, mockNerdGraphAlias(query, "alertsNrqlConditionBaselineUpdate"), "BASELINE")
+	case strings.Contains(query, "nrqlCondition(id:"):
+		data, errs = m.nerdGraphNrqlCondition(vars)
+	case strings.Contains(query, "nrqlConditionsSearch("):
+		data, errs = m.nerdGraphNrqlConditionSearch(vars)
+	case strings.Contains(query, "workloadCreate("):
+		data, errs = m.nerdGraphWorkloadCreate(vars)
+	case strings.Contains(query, "workloadUpdate("):
+		data, errs = m.nerdGraphWorkloadUpdate(vars)
+	case strings.Contains(query, "workloadDelete("):
+		data, errs = m.nerdGraphWorkloadDelete(vars)
+	case strings.Contains(query, "collection(guid:"):
+		data, errs = m.nerdGraphWorkload(vars)
+	case strings.Contains(query, "collections {"):
+		data, errs = m.nerdGraphWorkloads(vars)
+	case strings.Contains(query, "taggingAddTagsToEntity("):
+		data, errs = m.nerdGraphAddTags(vars)
+	case strings.Contains(query, "taggingDeleteTagFromEntity("):
+		data, errs = m.nerdGraphDeleteTags(vars)
+	case strings.Contains(query, "entity(guid:"):
+		data, errs = m.nerdGraphEntityTags(vars)
+	case strings.Contains(query, "account(id:"):
+		data, errs = m.nerdGraphAccount(vars)
+	case strings.Contains(query, "user {"):
+		data = map[string]interface{}{
+			"actor": map[string]interface{}{
+				"user": map[string]interface{}{"id": 1, "email": "tf-test@example.com"},
+			},
+		}
+	default:
+		log.Printf("[WARN] mock API server: unsupported NerdGraph query %s", query)
+		errs = []interface{}{map[string]interface{}{"message": "mock API server does not implement this query"}}
+	}
+
+	body := map[string]interface{}{"data": data}
+	if len(errs) > 0 {
+		body["errors"] = errs
+	}
+
+	m.writeJSON(w, http.StatusOK, body)
+}
+
+// mockNerdGraphAlias returns the name a root field is returned under, which
+// is its alias when the query gives it one.
+func mockNerdGraphAlias(query string, field string) string {
+	if m := regexp.MustCompile(`(\w+)\s*:\s*` + field + `\(`).FindStringSubmatch(query); m != nil {
+		return m[1]
+	}
+
+	return field
+}
+
+func mockNerdGraphAccount(accountID interface{}, account map[string]interface{}) map[string]interface{} {
+	account["id"] = accountID
+
+	return map[string]interface{}{
+		"actor": map[string]interface{}{
+			"account": account,
+		},
+	}
+}
+
+func (m *mockAPIServer) nerdGraphAccount(vars map[string]interface{}) (interface{}, []interface{}) {
+	accountID := mockNerdGraphInt(vars["accountId"])
+	if accountID != mockAccountID {
+		return map[string]interface{}{"actor": map[string]interface{}{"account": nil}}, []interface{}{
+			map[string]interface{}{"message": fmt.Sprintf("Account %d not authorized", accountID)},
+		}
+	}
+
+	return mockNerdGraphAccount(accountID, map[string]interface{}{"name": "Mock Account"}), nil
+}
+
+func mockNerdGraphInt(v interface{}) int {
+	switch t := v.(type) {
+	case int:
+		return t
+	case float64:
+		return int(t)
+	case string:
+		return mockAtoi(t)
+	}
+
+	return 0
+}
+
+func (m *mockAPIServer) nerdGraphPolicyResult(id int, accountID interface{}) map[string]interface{} {
+	policy := m.policies[id]
+
+	return map[string]interface{}{
+		"id":                 strconv.Itoa(id),
+		"name":               policy["name"],
+		"incidentPreference": policy["incident_preference"],
+		"accountId":          accountID,
+	}
+}
+
+func (m *mockAPIServer) nerdGraphPolicyCreate(vars map[string]interface{}) (interface{}, []interface{}) {
+	input, _ := vars["policy"].(map[string]interface{})
+
+	id := m.newID()
+	m.policies[id] = map[string]interface{}{
+		"id":                  id,
+		"name":                input["name"],
+		"incident_preference": input["incidentPreference"],
+	}
+
+	return map[string]interface{}{
+		"alertsPolicyCreate": m.nerdGraphPolicyResult(id, vars["accountID"]),
+	}, nil
+}
+
+func (m *mockAPIServer) nerdGraphPolicyUpdate(vars map[string]interface{}) (interface{}, []interface{}) {
+	id := mockNerdGraphInt(vars["policyID"])
+	if _, ok := m.policies[id]; !ok {
+		return nil, mockNerdGraphNotFound
+	}
+
+	input, _ := vars["policy"].(map[string]interface{})
+	m.policies[id]["name"] = input["name"]
+	m.policies[id]["incident_preference"] = input["incidentPreference"]
+
+	return map[string]interface{}{
+		"alertsPolicyUpdate": m.nerdGraphPolicyResult(id, vars["accountID"]),
+	}, nil
+}
+
+func (m *mockAPIServer) nerdGraphPolicyDelete(vars map[string]interface{}) (interface{}, []interface{}) {
+	id := mockNerdGraphInt(vars["policyID"])
+	if _, ok := m.policies[id]; !ok {
+		return nil, mockNerdGraphNotFound
+	}
+
+	m.removePolicy(id)
+
+	return map[string]interface{}{
+		"alertsPolicyDelete": map[string]interface{}{"id": strconv.Itoa(id)},
+	}, nil
+}
+
+func (m *mockAPIServer) nerdGraphPolicy(vars map[string]interface{}) (interface{}, []interface{}) {
+	id := mockNerdGraphInt(vars["policyID"])
+	if _, ok := m.policies[id]; !ok {
+		return nil, mockNerdGraphNotFound
+	}
+
+	return mockNerdGraphAccount(vars["accountID"], map[string]interface{}{
+		"alerts": map[string]interface{}{
+			"policy": m.nerdGraphPolicyResult(id, vars["accountID"]),
+		},
+	}), nil
+}
+
+func (m *mockAPIServer) nerdGraphPolicySearch(vars map[string]interface{}) (interface{}, []interface{}) {
+	criteria, _ := vars["searchCriteria"].(map[string]interface{})
+	rawIDs, _ := criteria["ids"].([]interface{})
+
+	wanted := map[int]bool{}
+	for _, id := range rawIDs {
+		wanted[mockNerdGraphInt(id)] = true
+	}
+
+	policies := []interface{}{}
+	for _, id := range m.sortedPolicyIDs() {
+		if len(wanted) == 0 || wanted[id] {
+			policies = append(policies, m.nerdGraphPolicyResult(id, vars["accountID"]))
+		}
+	}
+
+	return mockNerdGraphAccount(vars["accountID"], map[string]interface{}{
+		"alerts": map[string]interface{}{
+			"policiesSearch": map[string]interface{}{
+				"nextCursor": nil,
+				"totalCount": len(policies),
+				"policies":   policies,
+			},
+		},
+	}), nil
+}
+
+func (m *mockAPIServer) nerdGraphNrqlConditionCreate(vars map[string]interface{}, field string, conditionType string) (interface{}, []interface{}) {
+	policyID := mockNerdGraphInt(vars["policyId"])
+	if _, ok := m.policies[policyID]; !ok {
+		return nil, mockNerdGraphNotFound
+	}
+
+	condition, _ := vars["condition"].(map[string]interface{})
+
+	id := m.newID()
+	condition["id"] = strconv.Itoa(id)
+	condition["policyId"] = strconv.Itoa(policyID)
+	condition["type"] = conditionType
+	mockNrqlConditionSignalDefaults(condition)
+	m.nrqlConditions[id] = &mockRecord{PolicyID: policyID, Data: condition}
+
+	return map[string]interface{}{field: condition}, nil
+}
+
+func (m *mockAPIServer) nerdGraphNrqlConditionUpdate(vars map[string]interface{}, field string, conditionType string) (interface{}, []interface{}) {
+	id := mockNerdGraphInt(vars["id"])
+
+	record, ok := m.nrqlConditions[id]
+	if !ok {
+		return nil, mockNerdGraphNotFound
+	}
+
+	condition, _ := vars["condition"].(map[string]interface{})
+	condition["id"] = strconv.Itoa(id)
+	condition["policyId"] = strconv.Itoa(record.PolicyID)
+	condition["type"] = conditionType
+	mockNrqlConditionSignalDefaults(condition)
+	record.Data = condition
+
+	return map[string]interface{}{field: condition}, nil
+}
+
+// mockNrqlConditionSignalDefaults fills in the signal settings NerdGraph
+// gives NRQL conditions that are not given any.
+func mockNrqlConditionSignalDefaults(condition map[string]interface{}) {
+	signal, _ := condition["signal"].(map[string]interface{})
+	if signal == nil {
+		signal = map[string]interface{}{}
+	}
+	if _, ok := signal["aggregationWindow"]; !ok {
+		signal["aggregationWindow"] = 60
+	}
+	if _, ok := signal["fillOption"]; !ok {
+		signal["fillOption"] = "NONE"
+	}
+	condition["signal"] = signal
+
+	if _, ok := condition["expiration"]; !ok {
+		condition["expiration"] = map[string]interface{}{
+			"closeViolationsOnExpiration": false,
+			"expirationDuration":          nil,
+			"openViolationOnExpiration":   false,
+		}
+	}
+}
+
+func (m *mockAPIServer) nerdGraphNrqlCondition(vars map[string]interface{}) (interface{}, []interface{}) {
+	record, ok := m.nrqlConditions[mockNerdGraphInt(vars["id"])]
+	if !ok {
+		return nil, mockNerdGraphNotFound
+	}
+
+	return mockNerdGraphAccount(vars["accountId"], map[string]interface{}{
+		"alerts": map[string]interface{}{
+			"nrqlCondition": record.Data,
+		},
+	}), nil
+}
+
+func (m *mockAPIServer) nerdGraphNrqlConditionSearch(vars map[string]interface{}) (interface{}, []interface{}) {
+	criteria, _ := vars["searchCriteria"].(map[string]interface{})
+	policyID := mockNerdGraphInt(criteria["policyId"])
+	name, _ := criteria["name"].(string)
+
+	ids := map[int]bool{}
+	for id, record := range m.nrqlConditions {
+		if (policyID == 0 || record.PolicyID == policyID) && (name == "" || record.Data["name"] == name) {
+			ids[id] = true
+		}
+	}
+
+	conditions := []interface{}{}
+	for _, id := range mockSortedIDs(ids) {
+		conditions = append(conditions, m.nrqlConditions[id].Data)
+	}
+
+	return mockNerdGraphAccount(vars["accountId"], map[string]interface{}{
+		"alerts": map[string]interface{}{
+			"nrqlConditionsSearch": map[string]interface{}{
+				"nextCursor":     nil,
+				"totalCount":     len(conditions),
+				"nrqlConditions": conditions,
+			},
+		},
+	}), nil
+}
+
+func (m *mockAPIServer) saveWorkload(workload map[string]interface{}, input map[string]interface{}) {
+	workload["name"] = input["name"]
+
+	entities := []interface{}{}
+	guids, _ := input["entityGuids"].([]interface{})
+	for _, guid := range guids {
+		entities = append(entities, map[string]interface{}{"guid": guid})
+	}
+
+	workload["entities"] = entities
+
+	queries := []interface{}{}
+	rawQueries, _ := input["entitySearchQueries"].([]interface{})
+	for _, q := range rawQueries {
+		query := q.(map[string]interface{})
+		queries = append(queries, map[string]interface{}{
+			"id":    m.newID(),
+			"query": query["query"],
+		})
+	}
+
+	workload["entitySearchQueries"] = queries
+
+	scopeAccounts := map[string]interface{}{"accountIds": []interface{}{}}
+	if scope, ok := input["scopeAccounts"].(map[string]interface{}); ok {
+		scopeAccounts["accountIds"] = scope["accountIds"]
+	}
+
+	workload["scopeAccounts"] = scopeAccounts
+}
+
+func (m *mockAPIServer) nerdGraphWorkloadCreate(vars map[string]interface{}) (interface{}, []interface{}) {
+	accountID := mockNerdGraphInt(vars["accountId"])
+	input, _ := vars["workload"].(map[string]interface{})
+
+	id := m.newID()
+	guid := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%d|NR1|WORKLOAD|%d", accountID, id)))
+
+	workload := map[string]interface{}{
+		"id":        id,
+		"guid":      guid,
+		"account":   map[string]interface{}{"id": accountID, "name": "Mock Account"},
+		"permalink": fmt.Sprintf("%s/redirect/entity/%s", m.URL(), guid),
+		"createdAt": 1590000000000,
+	}
+
+	m.saveWorkload(workload, input)
+	m.workloads[guid] = workload
+
+	return map[string]interface{}{"workloadCreate": workload}, nil
+}
+
+func (m *mockAPIServer) nerdGraphWorkloadUpdate(vars map[string]interface{}) (interface{}, []interface{}) {
+	workload, ok := m.workloads[fmt.Sprint(vars["guid"])]
+	if !ok {
+		return nil, mockNerdGraphNotFound
+	}
+
+	input, _ := vars["workload"].(map[string]interface{})
+	m.saveWorkload(workload, input)
+
+	return map[string]interface{}{"workloadUpdate": workload}, nil
+}
+
+func (m *mockAPIServer) nerdGraphWorkloadDelete(vars map[string]interface{}) (interface{}, []interface{}) {
+	guid := fmt.Sprint(vars["guid"])
+
+	workload, ok := m.workloads[guid]
+	if !ok {
+		return nil, mockNerdGraphNotFound
+	}
+
+	delete(m.workloads, guid)
+
+	return map[string]interface{}{"workloadDelete": workload}, nil
+}
+
+func (m *mockAPIServer) nerdGraphWorkload(vars map[string]interface{}) (interface{}, []interface{}) {
+	workload, ok := m.workloads[fmt.Sprint(vars["guid"])]
+	if !ok {
+		return nil, mockNerdGraphNotFound
+	}
+
+	return mockNerdGraphAccount(vars["accountId"], map[string]interface{}{
+		"workload": map[string]interface{}{
+			"collection": workload,
+		},
+	}), nil
+}
+
+func (m *mockAPIServer) nerdGraphWorkloads(vars map[string]interface{}) (interface{}, []interface{}) {
+	accountID := mockNerdGraphInt(vars["accountId"])
+
+	guids := make([]string, 0, len(m.workloads))
+	for guid := range m.workloads {
+		guids = append(guids, guid)
+	}
+	sort.Strings(guids)
+
+	workloads := []interface{}{}
+	for _, guid := range guids {
+		workload := m.workloads[guid]
+		if mockNerdGraphInt(workload["account"].(map[string]interface{})["id"]) == accountID {
+			workloads = append(workloads, workload)
+		}
+	}
+
+	return mockNerdGraphAccount(accountID, map[string]interface{}{
+		"workload": map[string]interface{}{
+			"collections": workloads,
+		},
+	}), nil
+}
+
+// mockNerdGraphField returns a field of an input object. Tag inputs are sent
+// with the field names of the client's structs, which have no JSON tags.
+func mockNerdGraphField(input map[string]interface{}, name string) interface{} {
+	if v, ok := input[name]; ok {
+		return v
+	}
+
+	return input[strings.ToUpper(name[:1])+name[1:]]
+}
+
+func (m *mockAPIServer) nerdGraphAddTags(vars map[string]interface{}) (interface{}, []interface{}) {
+	guid := fmt.Sprint(vars["guid"])
+	if m.entityTags[guid] == nil {
+		m.entityTags[guid] = map[string][]string{}
+	}
+
+	tags, _ := vars["tags"].([]interface{})
+	for _, t := range tags {
+		tag := t.(map[string]interface{})
+		key := fmt.Sprint(mockNerdGraphField(tag, "key"))
+		values, _ := mockNerdGraphField(tag, "values").([]interface{})
+
+		for _, v := range values {
+			m.entityTags[guid][key] = append(m.entityTags[guid][key], fmt.Sprint(v))
+		}
+	}
+
+	return map[string]interface{}{
+		"taggingAddTagsToEntity": map[string]interface{}{"errors": []interface{}{}},
+	}, nil
+}
+
+func (m *mockAPIServer) nerdGraphDeleteTags(vars map[string]interface{}) (interface{}, []interface{}) {
+	guid := fmt.Sprint(vars["guid"])
+
+	keys, _ := vars["tagKeys"].([]interface{})
+	for _, k := range keys {
+		delete(m.entityTags[guid], fmt.Sprint(k))
+	}
+
+	return map[string]interface{}{
+		"taggingDeleteTagFromEntity": map[string]interface{}{"errors": []interface{}{}},
+	}, nil
+}
+
+func (m *mockAPIServer) nerdGraphEntityTags(vars map[string]interface{}) (interface{}, []interface{}) {
+	entityTags := m.entityTags[fmt.Sprint(vars["guid"])]
+
+	keys := make([]string, 0, len(entityTags))
+	for k := range entityTags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tags := []interface{}{}
+	for _, k := range keys {
+		tags = append(tags, map[string]interface{}{"key": k, "values": entityTags[k]})
+	}
+
+	return map[string]interface{}{
+		"actor": map[string]interface{}{
+			"entity": map[string]interface{}{"tags": tags},
+		},
+	}, nil
+}
+
+func TestMockAPIServer(t *testing.T) {
+	srv := newMockAPIServer()
+	defer srv.Close()
+
+	cfg := srv.config()
+
+	client, err := cfg.Client()
+	require.NoError(t, err)
+
+	// Policies created through NerdGraph are visible through the REST API.
+	created, err := client.Alerts.CreatePolicyMutation(mockAccountID, alerts.AlertsPolicyInput{
+		Name:               "tf-test",
+		IncidentPreference: alerts.AlertsIncidentPreferenceTypes.PER_POLICY,
+	})
+	require.NoError(t, err)
+
+	policies, err := client.Alerts.ListPolicies(&alerts.ListPoliciesParams{Name: "tf-test"})
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	require.Equal(t, created.ID, strconv.Itoa(policies[0].ID))
+
+	_, err = client.Alerts.DeletePolicy(policies[0].ID)
+	require.NoError(t, err)
+
+	_, err = client.Alerts.QueryPolicy(mockAccountID, created.ID)
+	require.IsType(t, &errors.NotFound{}, err)
+
+	resp, err := http.Get(srv.URL() + "/v2/alerts_policies.json")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
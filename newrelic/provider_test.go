@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -21,6 +22,7 @@ var (
 	testAccProviders                map[string]terraform.ResourceProvider
 	testAccProvider                 *schema.Provider
 	testAccountID                   int
+	testAccMockAPIServer            *mockAPIServer
 	//testAccCleanupComplete          = false
 )
 
@@ -28,6 +30,13 @@ func init() {
 	testAccExpectedAlertChannelName = fmt.Sprintf("%s tf-test@example.com", acctest.RandString(5))
 	testAccExpectedApplicationName = fmt.Sprintf("tf_test_%s", acctest.RandString(10))
 	testAccExpectedAlertPolicyName = fmt.Sprintf("tf_test_%s", acctest.RandString(10))
+
+	// The mock API server sets the provider environment,
+	// so it must be started before anything below reads it.
+	if os.Getenv("NEWRELIC_MOCK_API") != "" {
+		testAccStartMockAPIServer()
+	}
+
 	testAccProvider = Provider().(*schema.Provider)
 	testAccProviders = map[string]terraform.ResourceProvider{
 		"newrelic": testAccProvider,
@@ -52,6 +61,23 @@ func TestProviderImpl(t *testing.T) {
 	var _ terraform.ResourceProvider = Provider()
 }
 
+func TestProvider_ReadOnly(t *testing.T) {
+	provider := Provider().(*schema.Provider)
+	meta := &ProviderConfig{ReadOnly: true}
+
+	r := provider.ResourcesMap["newrelic_alert_policy"]
+	d := r.TestResourceData()
+	d.SetId("123")
+
+	// The provider config has no client, so any API call would panic.
+	require.EqualError(t, r.Create(d, meta), "cannot create newrelic_alert_policy: the provider is configured with read_only = true")
+	require.EqualError(t, r.Update(d, meta), "cannot update newrelic_alert_policy: the provider is configured with read_only = true")
+	require.EqualError(t, r.Delete(d, meta), "cannot delete newrelic_alert_policy: the provider is configured with read_only = true")
+
+	// Resources that cannot be updated still have no Update function.
+	require.Nil(t, provider.ResourcesMap["newrelic_insights_event"].Update)
+}
+
 func TestProviderConfig(t *testing.T) {
 	c := ProviderConfig{
 		PersonalAPIKey: "abc123",
@@ -65,7 +91,32 @@ func TestProviderConfig(t *testing.T) {
 	}
 }
 
+func testAccStartMockAPIServer() {
+	testAccMockAPIServer = newMockAPIServer()
+	url := testAccMockAPIServer.URL()
+
+	env := map[string]string{
+		"NEWRELIC_API_KEY":                mockAPIKey,
+		"NEWRELIC_PERSONAL_API_KEY":       mockPersonalAPIKey,
+		"NEWRELIC_ACCOUNT_ID":             strconv.Itoa(mockAccountID),
+		"NEWRELIC_API_URL":                url + "/v2",
+		"NEWRELIC_SYNTHETICS_API_URL":     url + "/synthetics/api",
+		"NEWRELIC_INFRASTRUCTURE_API_URL": url + "/infra/v2",
+		"NEWRELIC_NERDGRAPH_API_URL":      url + "/graphql",
+	}
+
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
+	// The mock API server provides its own credentials.
+	if testAccMockAPIServer != nil {
+		testAccMockAPIServer.reportApplication(testAccExpectedApplicationName)
+		return
+	}
+
 	if v := os.Getenv("NEWRELIC_API_KEY"); v == "" {
 		t.Fatal("NEWRELIC_API_KEY must be set for acceptance tests")
 	}
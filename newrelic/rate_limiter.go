@@ -0,0 +1,199 @@
+package newrelic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiFamily identifies a New Relic API with its own request limits.
+type apiFamily string
+
+const (
+	apiFamilyREST           apiFamily = "rest"
+	apiFamilyNerdGraph      apiFamily = "nerdgraph"
+	apiFamilySynthetics     apiFamily = "synthetics"
+	apiFamilyInfrastructure apiFamily = "infrastructure"
+)
+
+// defaultRateLimits are the requests per second allowed for each API family,
+// which keep a run with Terraform's default parallelism well within the
+// per-key limits of each API.
+var defaultRateLimits = map[apiFamily]float64{
+	apiFamilyREST:           10,
+	apiFamilyNerdGraph:      10,
+	apiFamilySynthetics:     5,
+	apiFamilyInfrastructure: 10,
+}
+
+// RateLimiter holds a token bucket for each API family, shared by every
+// request made by the provider.
+type RateLimiter struct {
+	buckets  map[apiFamily]*tokenBucket
+	baseURLs map[apiFamily]string
+}
+
+// NewRateLimiter returns a RateLimiter for the given base URL of each API
+// family. Families without a rate, or with a rate of zero, are not limited.
+func NewRateLimiter(baseURLs map[apiFamily]string, rates map[apiFamily]float64) *RateLimiter {
+	l := &RateLimiter{
+		buckets:  map[apiFamily]*tokenBucket{},
+		baseURLs: baseURLs,
+	}
+
+	for family, rate := range rates {
+		if rate > 0 {
+			l.buckets[family] = newTokenBucket(rate)
+		}
+	}
+
+	return l
+}
+
+// Wait blocks until the API family of the request URL allows another request.
+func (l *RateLimiter) Wait(ctx context.Context, url string) error {
+	bucket, ok := l.buckets[l.family(url)]
+	if !ok {
+		return nil
+	}
+
+	return bucket.Wait(ctx)
+}
+
+// family returns the API family whose base URL is the longest prefix of url,
+// since the REST and NerdGraph APIs share a host.
+func (l *RateLimiter) family(url string) apiFamily {
+	var match apiFamily
+	var matchLen int
+
+	for family, base := range l.baseURLs {
+		if base != "" && strings.HasPrefix(url, base) && len(base) > matchLen {
+			match, matchLen = family, len(base)
+		}
+	}
+
+	return match
+}
+
+// tokenBucket allows rate requests per second on average, and bursts of up to
+// rate requests after a quiet period.
+type tokenBucket struct {
+	sync.Mutex
+
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	burst := rate
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// Wait takes a token from the bucket, waiting for one to become available
+// when the bucket is empty.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	wait := b.reserve()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		b.Lock()
+		b.tokens++
+		b.Unlock()
+
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token, letting the balance go negative when the bucket is
+// empty, and returns how long to wait until the token is actually available.
+func (b *tokenBucket) reserve() time.Duration {
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// rateLimitTransport waits for the rate limiter before sending each request.
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	limiter   *RateLimiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context(), req.URL.String()); err != nil {
+		return nil, err
+	}
+
+	return t.transport.RoundTrip(req)
+}
+
+// expandRateLimits validates the rate_limits provider attribute and merges it
+// with the default rate of each API family.
+func expandRateLimits(cfg map[string]interface{}) (map[apiFamily]float64, error) {
+	rates := map[apiFamily]float64{}
+	for family, rate := range defaultRateLimits {
+		rates[family] = rate
+	}
+
+	for k, v := range cfg {
+		family := apiFamily(k)
+		if _, ok := defaultRateLimits[family]; !ok {
+			return nil, fmt.Errorf("invalid rate_limits key %q, must be one of %s", k, strings.Join(rateLimitKeys(), ", "))
+		}
+
+		rate, ok := v.(int)
+		if !ok || rate < 0 {
+			return nil, fmt.Errorf("invalid rate_limits value for %q, must be a non-negative number of requests per second", k)
+		}
+
+		rates[family] = float64(rate)
+	}
+
+	return rates, nil
+}
+
+func rateLimitKeys() []string {
+	keys := []string{}
+	for family := range defaultRateLimits {
+		keys = append(keys, string(family))
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,83 @@
+package newrelic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testRateLimiterBaseURLs = map[apiFamily]string{
+	apiFamilyREST:           "https://api.newrelic.com/v2",
+	apiFamilyNerdGraph:      "https://api.newrelic.com/graphql",
+	apiFamilySynthetics:     "https://synthetics.newrelic.com/synthetics/api",
+	apiFamilyInfrastructure: "https://infra-api.newrelic.com/v2",
+}
+
+func TestRateLimiter_Family(t *testing.T) {
+	l := NewRateLimiter(testRateLimiterBaseURLs, defaultRateLimits)
+
+	require.Equal(t, apiFamilyREST, l.family("https://api.newrelic.com/v2/alerts_policies.json"))
+	require.Equal(t, apiFamilyNerdGraph, l.family("https://api.newrelic.com/graphql"))
+	require.Equal(t, apiFamilySynthetics, l.family("https://synthetics.newrelic.com/synthetics/api/v4/monitors"))
+	require.Equal(t, apiFamilyInfrastructure, l.family("https://infra-api.newrelic.com/v2/alerts/conditions"))
+	require.Equal(t, apiFamily(""), l.family("https://example.com"))
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l := NewRateLimiter(testRateLimiterBaseURLs, map[apiFamily]float64{
+		apiFamilyREST: 20,
+	})
+
+	// The initial burst is not delayed.
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		require.NoError(t, l.Wait(context.Background(), "https://api.newrelic.com/v2/applications.json"))
+	}
+	require.True(t, time.Since(start) < 40*time.Millisecond)
+
+	// Families without a rate are not limited.
+	for i := 0; i < 100; i++ {
+		require.NoError(t, l.Wait(context.Background(), "https://api.newrelic.com/graphql"))
+	}
+
+	// Once the burst is spent, requests are spaced out at the configured rate.
+	start = time.Now()
+	require.NoError(t, l.Wait(context.Background(), "https://api.newrelic.com/v2/applications.json"))
+	require.True(t, time.Since(start) >= 40*time.Millisecond)
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	l := NewRateLimiter(testRateLimiterBaseURLs, map[apiFamily]float64{
+		apiFamilySynthetics: 0.1,
+	})
+	url := "https://synthetics.newrelic.com/synthetics/api/v4/monitors"
+
+	require.NoError(t, l.Wait(context.Background(), url))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.Equal(t, context.DeadlineExceeded, l.Wait(ctx, url))
+}
+
+func TestExpandRateLimits(t *testing.T) {
+	rates, err := expandRateLimits(map[string]interface{}{
+		"nerdgraph":  2,
+		"synthetics": 0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[apiFamily]float64{
+		apiFamilyREST:           10,
+		apiFamilyNerdGraph:      2,
+		apiFamilySynthetics:     0,
+		apiFamilyInfrastructure: 10,
+	}, rates)
+
+	_, err = expandRateLimits(map[string]interface{}{"insights": 1})
+	require.EqualError(t, err, `invalid rate_limits key "insights", must be one of infrastructure, nerdgraph, rest, synthetics`)
+
+	_, err = expandRateLimits(map[string]interface{}{"rest": -1})
+	require.Error(t, err)
+}
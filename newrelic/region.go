@@ -0,0 +1,125 @@
+package newrelic
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/newrelic/newrelic-client-go/pkg/region"
+)
+
+// regionEndpoints holds the endpoint URLs of each New Relic region, keyed by
+// the provider attribute that overrides them.
+var regionEndpoints = map[region.Name]map[string]string{
+	region.US: {
+		"api_url":                "https://api.newrelic.com/v2",
+		"infrastructure_api_url": "https://infra-api.newrelic.com/v2",
+		"insights_insert_url":    "https://insights-collector.newrelic.com/v1/accounts",
+		"insights_query_url":     "https://insights-api.newrelic.com/v1/accounts",
+		"nerdgraph_api_url":      "https://api.newrelic.com/graphql",
+		"synthetics_api_url":     "https://synthetics.newrelic.com/synthetics/api",
+	},
+	region.EU: {
+		"api_url":                "https://api.eu.newrelic.com/v2",
+		"infrastructure_api_url": "https://infra-api.eu.newrelic.com/v2",
+		"insights_insert_url":    "https://insights-collector.eu.newrelic.com/v1/accounts",
+		"insights_query_url":     "https://insights-api.eu.newrelic.com/v1/accounts",
+		"nerdgraph_api_url":      "https://api.eu.newrelic.com/graphql",
+		"synthetics_api_url":     "https://synthetics.eu.newrelic.com/synthetics/api",
+	},
+	region.Staging: {
+		"api_url":                "https://staging-api.newrelic.com/v2",
+		"infrastructure_api_url": "https://staging-infra-api.newrelic.com/v2",
+		"insights_insert_url":    "https://staging-insights-collector.newrelic.com/v1/accounts",
+		"insights_query_url":     "https://staging-insights-api.newrelic.com/v1/accounts",
+		"nerdgraph_api_url":      "https://staging-api.newrelic.com/graphql",
+		"synthetics_api_url":     "https://staging-synthetics.newrelic.com/synthetics/api",
+	},
+}
+
+// regionNames lists the accepted values of the region attribute.
+var regionNames = []string{
+	region.US.String(),
+	region.EU.String(),
+	region.Staging.String(),
+}
+
+// resolveRegionEndpoints returns the endpoint URL for every region-scoped
+// attribute. URLs set explicitly in overrides are kept as they are, and the
+// rest are taken from the named region. Without a name, the region is inferred
+// from the overrides and falls back to US. Overrides pointing at a different
+// New Relic region than the one configured, or at different regions from each
+// other, are rejected.
+func resolveRegionEndpoints(regionName string, overrides map[string]string) (map[string]string, error) {
+	var name region.Name
+
+	if regionName != "" {
+		var err error
+		if name, err = region.Parse(regionName); err != nil {
+			return nil, fmt.Errorf("invalid region %q, must be one of %s", regionName, strings.Join(regionNames, ", "))
+		}
+	}
+
+	attributes := []string{}
+	for attr := range overrides {
+		attributes = append(attributes, attr)
+	}
+
+	sort.Strings(attributes)
+
+	// When no region is configured, the first override that belongs to
+	// a known region sets the region the others must agree with.
+	source := fmt.Sprintf("region is set to %s", name)
+	for _, attr := range attributes {
+		overrideRegion, ok := regionForURL(overrides[attr])
+		if !ok {
+			continue
+		}
+
+		if name == "" {
+			name = overrideRegion
+			source = fmt.Sprintf("%s points at the %s region", attr, name)
+			continue
+		}
+
+		if overrideRegion != name {
+			return nil, fmt.Errorf("mixed-region configuration: %s points at the %s region, but %s", attr, overrideRegion, source)
+		}
+	}
+
+	if name == "" {
+		name = region.Default
+	}
+
+	endpoints := map[string]string{}
+	for attr, u := range regionEndpoints[name] {
+		endpoints[attr] = u
+
+		if override := overrides[attr]; override != "" {
+			endpoints[attr] = override
+		}
+	}
+
+	return endpoints, nil
+}
+
+// regionForURL returns the New Relic region a URL belongs to. URLs that do not
+// match a known New Relic endpoint, such as proxies, return false.
+func regionForURL(rawURL string) (region.Name, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+
+	for name, endpoints := range regionEndpoints {
+		for _, endpoint := range endpoints {
+			e, _ := url.Parse(endpoint)
+			if strings.EqualFold(u.Host, e.Host) {
+				return name, true
+			}
+		}
+	}
+
+	return "", false
+}
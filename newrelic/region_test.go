@@ -0,0 +1,55 @@
+package newrelic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRegionEndpoints(t *testing.T) {
+	endpoints, err := resolveRegionEndpoints("", map[string]string{})
+	require.NoError(t, err)
+	require.Equal(t, regionEndpoints["US"], endpoints)
+
+	endpoints, err = resolveRegionEndpoints("eu", map[string]string{})
+	require.NoError(t, err)
+	require.Equal(t, regionEndpoints["EU"], endpoints)
+	require.Equal(t, "https://insights-collector.eu.newrelic.com/v1/accounts", endpoints["insights_insert_url"])
+}
+
+func TestResolveRegionEndpoints_Overrides(t *testing.T) {
+	endpoints, err := resolveRegionEndpoints("EU", map[string]string{
+		"api_url":            "http://localhost:8080/v2",
+		"synthetics_api_url": "https://synthetics.eu.newrelic.com/synthetics/api",
+		"nerdgraph_api_url":  "",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost:8080/v2", endpoints["api_url"])
+	require.Equal(t, "https://api.eu.newrelic.com/graphql", endpoints["nerdgraph_api_url"])
+}
+
+func TestResolveRegionEndpoints_InferredRegion(t *testing.T) {
+	endpoints, err := resolveRegionEndpoints("", map[string]string{
+		"api_url": "https://api.eu.newrelic.com/v2",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://infra-api.eu.newrelic.com/v2", endpoints["infrastructure_api_url"])
+}
+
+func TestResolveRegionEndpoints_MixedRegions(t *testing.T) {
+	_, err := resolveRegionEndpoints("US", map[string]string{
+		"synthetics_api_url": "https://synthetics.eu.newrelic.com/synthetics/api",
+	})
+	require.EqualError(t, err, "mixed-region configuration: synthetics_api_url points at the EU region, but region is set to US")
+
+	_, err = resolveRegionEndpoints("", map[string]string{
+		"api_url":             "https://api.eu.newrelic.com/v2",
+		"insights_insert_url": "https://insights-collector.newrelic.com/v1/accounts",
+	})
+	require.EqualError(t, err, "mixed-region configuration: insights_insert_url points at the US region, but api_url points at the EU region")
+}
+
+func TestResolveRegionEndpoints_InvalidRegion(t *testing.T) {
+	_, err := resolveRegionEndpoints("APAC", map[string]string{})
+	require.EqualError(t, err, `invalid region "APAC", must be one of US, EU, Staging`)
+}
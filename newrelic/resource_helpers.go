@@ -1,107 +1,205 @@
 package newrelic
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/errors"
+
+	"github.com/terraform-providers/terraform-provider-newrelic/internal/compoundid"
 )
 
-// Generates a compound ID out of a slice of strings.
-// This ID could contain metadata as the last string in the slice.
-// e.g. 425235:2384930:someMetadata
-func resourceGenerateCompoundID(idItems []string) ([]int, error) {
-	nonMetadataIDCount := len(idItems) - 1
-	ids := make([]int, nonMetadataIDCount)
-	for i, id := range idItems[:nonMetadataIDCount] {
-		intID, err := strconv.Atoi(id)
+// The time allowed for each operation of a resource, unless a different one
+// is given in the resource's `timeouts` block.
+const defaultResourceTimeout = 20 * time.Minute
+
+// Returns the schema of a resource ID that is a single integer ID, such as
+// the ID of a dashboard.
+func integerID(name string) *compoundid.Schema {
+	return &compoundid.Schema{
+		Formats: []compoundid.Format{
+			{
+				Version: 1,
+				Parts: []compoundid.Part{
+					{Name: name, Kind: compoundid.Int},
+				},
+			},
+		},
+	}
+}
+
+// The ID of an alert condition, which is only unique within its policy.
+var policyConditionID = &compoundid.Schema{
+	Formats: []compoundid.Format{
+		{
+			Version: 1,
+			Parts: []compoundid.Part{
+				{Name: "policy_id", Kind: compoundid.Int},
+				{Name: "condition_id", Kind: compoundid.Int},
+			},
+		},
+	},
+}
+
+// The name form of alert condition IDs, as conditions are only unique within
+// their policy.
+var policyConditionNames = []string{"policy", "condition"}
+
+// Returns the lookup of alert conditions imported by name, which finds the
+// policy by its name and then the condition among the policy's conditions.
+// The given function lists the IDs of the conditions of a policy with a name,
+// optionally followed by the attribute parts of the resource's ID schema.
+func importAlertConditionByName(
+	kind string,
+	list func(client *nr.NewRelic, providerConfig *ProviderConfig, policyID int, name string) ([]string, error),
+) importLookupFunc {
+	return func(client *nr.NewRelic, providerConfig *ProviderConfig, d *schema.ResourceData, names []string) (string, error) {
+		policyID, err := findAlertPolicyIDByName(client, providerConfig, providerConfig.AccountID, names[0])
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
-		ids[i] = intID
-	}
+		conditionIDs, err := list(client, providerConfig, policyID, names[1])
+		if err != nil {
+			return "", err
+		}
+
+		conditionID, err := importMatch(kind, names[1], conditionIDs)
+		if err != nil {
+			return "", err
+		}
 
-	return ids, nil
+		return policyConditionID.New(policyID, conditionID), nil
+	}
 }
 
-// Handles importing of resources that might utilize a compound ID,
-// more specifically when metadata might be passed in the compoundID.
-//
-// The `defaultIDCount` argument represents the number of items that
-// make up a compound ID. This count excludes any appended metadata.
-//
-// e.g. "425235:2384930" contains 2 items as the default
+// Handles importing of resources that utilize a compound ID.
 //
-// The optional `attribute` argument provides an opportunity to
-// set a schema attribute with a metadata value if metadata is provided
-// in the compound ID as the LAST string in the compound ID.
-func resourceImportStateWithMetadata(defaultIDCount int, attribute string) schema.StateFunc {
+// The ID being imported is validated against the given schema, so that every
+// resource reports a malformed ID the same way. Parts of the ID that are
+// attributes rather than part of the resource ID, such as the type of an NRQL
+// alert condition, are set on the resource, and the ID is stored in its
+// current format.
+func resourceImportStateWithCompoundID(idSchema *compoundid.Schema) schema.StateFunc {
 	return func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-		if defaultIDCount == 0 {
-			return []*schema.ResourceData{d}, nil
+		id, err := idSchema.Parse(d.Id())
+		if err != nil {
+			return nil, err
 		}
 
-		idItems := strings.Split(d.Id(), ":")
-		idItemsCount := len(idItems)
-		nonMetadataIDCount := idItemsCount - 1
-
-		if idItemsCount < defaultIDCount {
-			return []*schema.ResourceData{}, fmt.Errorf("compound ID item count cannot be less than expected default ID item count: %v", idItems)
+		for attribute, value := range id.Attributes() {
+			if err := d.Set(attribute, value); err != nil {
+				log.Printf("[ERROR] setting attribute %s: %s", attribute, err)
+				return nil, err
+			}
 		}
 
-		if idItemsCount == defaultIDCount {
-			return []*schema.ResourceData{d}, nil
-		}
+		d.SetId(id.String())
+
+		return []*schema.ResourceData{d}, nil
+	}
+}
 
-		// The last item of a compound ID is the metadata
-		metadataValue := idItems[nonMetadataIDCount]
+// Looks up the ID of the object a resource imported by name refers to. The
+// names are given in the order of the keys of the resource's name form, and
+// the ID returned may be in any format of the resource's ID schema.
+type importLookupFunc func(client *nr.NewRelic, providerConfig *ProviderConfig, d *schema.ResourceData, names []string) (string, error)
+
+// Handles importing of resources that can be imported either by ID or by the
+// names of the objects they refer to, such as `name:Checkout` for an alert
+// policy, or `policy:Checkout/condition:High error rate` for an alert
+// condition. The keys are those of the name form, in order.
+//
+// Names are resolved with the given lookup function, and must match exactly
+// one object. The resolved ID is then imported as if it had been given.
+func resourceImportStateByName(idSchema *compoundid.Schema, keys []string, lookup importLookupFunc) schema.StateFunc {
+	importByID := resourceImportStateWithCompoundID(idSchema)
 
-		// Generate compound ID without the metadata suffix
-		// e.g. 583922:5231245 (<policyID>:<conditionID>)
-		ids, err := resourceGenerateCompoundID(idItems)
+	return func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		names, err := parseImportNames(d.Id(), keys)
 		if err != nil {
-			return []*schema.ResourceData{}, nil
+			return nil, err
 		}
 
-		// If an attribute is supplied, attempt to set
-		// with any provided metadata
-		if attribute != "" {
-
-			// In order to set the attribute correctly, we need to make sure that we
-			// understand the attribute type, so that we don't set a string to an int
-			// value.  First we fetch the Get() the attribute to determine what
-			// interface type is returned, and then perform any necessary conversion
-			// for that attribute before finally setting the attribute.
-
-			var v int
-
-			x := d.Get(attribute)
-
-			switch t := x.(type) {
-			case int:
-				v, err = strconv.Atoi(metadataValue)
-				if err != nil {
-					log.Printf("[ERROR] unable to convert type %T: %s", t, err)
-					return []*schema.ResourceData{}, err
-				}
-				err = d.Set(attribute, v)
-			default:
-				err = d.Set(attribute, metadataValue)
+		if names != nil {
+			client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+			if err != nil {
+				return nil, err
 			}
+			defer cancel()
 
+			id, err := lookup(client, meta.(*ProviderConfig), d, names)
 			if err != nil {
-				log.Printf("[ERROR] setting attribute %s: %s", attribute, err)
-				return []*schema.ResourceData{}, err
+				return nil, err
 			}
+
+			log.Printf("[INFO] resolved %s to ID %s", d.Id(), id)
+
+			d.SetId(id)
 		}
 
-		d.SetId(serializeIDs(ids))
+		return importByID(d, meta)
+	}
+}
 
-		return []*schema.ResourceData{d}, nil
+// Splits an ID given in a name form, such as `policy:<name>/condition:<name>`,
+// into its names. Names may hold any character, so each key after the first
+// is only matched when it follows a slash. A nil slice is returned for IDs
+// that are not in the name form.
+func parseImportNames(raw string, keys []string) ([]string, error) {
+	if !strings.HasPrefix(raw, keys[0]+":") {
+		return nil, nil
 	}
+
+	layout := make([]string, len(keys))
+	for i, key := range keys {
+		layout[i] = key + ":<name>"
+	}
+
+	names := make([]string, len(keys))
+	rest := raw[len(keys[0])+1:]
+
+	for i := range keys {
+		end := len(rest)
+
+		if i < len(keys)-1 {
+			end = strings.Index(rest, "/"+keys[i+1]+":")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid ID %q: expected %s", raw, strings.Join(layout, "/"))
+			}
+		}
+
+		names[i] = rest[:end]
+		if names[i] == "" {
+			return nil, fmt.Errorf("invalid ID %q: %s name must not be empty", raw, keys[i])
+		}
+
+		if i < len(keys)-1 {
+			rest = rest[end+len(keys[i+1])+2:]
+		}
+	}
+
+	return names, nil
+}
+
+// Returns the only ID of the objects of a kind found with a name, failing if
+// there are none or several of them, in which case the object must be
+// imported by ID.
+func importMatch(kind string, name string, ids []string) (string, error) {
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("no %s named %q was found", kind, name)
+	case 1:
+		return ids[0], nil
+	}
+
+	return "", fmt.Errorf("more than one %s is named %q (IDs %s), import it by ID instead", kind, name, strings.Join(ids, ", "))
 }
 
 // Selects the proper accountID for usage within a resource. An account ID provided
@@ -117,3 +215,59 @@ func selectAccountID(providerCondig *ProviderConfig, d *schema.ResourceData) int
 
 	return providerCondig.AccountID
 }
+
+// Returns a client for a single operation of a resource, which abandons
+// in-flight requests once the operation's timeout passes or Terraform is
+// interrupted. The returned function must be called once the operation is
+// done to release the timeout.
+//
+// The `timeoutKey` argument is one of schema.TimeoutCreate, TimeoutRead,
+// TimeoutUpdate or TimeoutDelete.
+func resourceClient(d *schema.ResourceData, meta interface{}, timeoutKey string) (*nr.NewRelic, context.CancelFunc, error) {
+	return meta.(*ProviderConfig).clientWithTimeout(d.Timeout(timeoutKey))
+}
+
+// Reads a resource right after it has been created. New Relic APIs are
+// eventually consistent, so an object that was just created can briefly be
+// reported as not found, which a resource's Read function would otherwise take
+// to mean the object was deleted outside of Terraform.
+//
+// While the object is not found, the read is retried until it is, or until the
+// create timeout of the resource, counted from when its creation started,
+// passes.
+func resourceReadAfterCreate(read schema.ReadFunc, d *schema.ResourceData, meta interface{}, started time.Time) error {
+	id := d.Id()
+
+	attempt := func() *resource.RetryError {
+		err := read(d, meta)
+		if _, ok := err.(*errors.NotFound); ok {
+			return resource.RetryableError(err)
+		}
+
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		// Read functions remove objects that are not found from the state.
+		if d.Id() == "" {
+			d.SetId(id)
+
+			log.Printf("[DEBUG] newly created resource %s not found yet, retrying", id)
+
+			return resource.RetryableError(fmt.Errorf("resource %s not found after being created", id))
+		}
+
+		return nil
+	}
+
+	timeout := d.Timeout(schema.TimeoutCreate) - time.Since(started)
+	if timeout <= 0 {
+		if err := attempt(); err != nil {
+			return err.Err
+		}
+
+		return nil
+	}
+
+	return resource.Retry(timeout, attempt)
+}
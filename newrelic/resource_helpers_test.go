@@ -0,0 +1,132 @@
+package newrelic
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func testResourceReadAfterCreateData(t *testing.T) *schema.ResourceData {
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"name": {Type: schema.TypeString, Optional: true},
+	}, map[string]interface{}{})
+	d.SetId("123")
+
+	return d
+}
+
+func TestResourceReadAfterCreate_RetriesNotFound(t *testing.T) {
+	d := testResourceReadAfterCreateData(t)
+
+	var reads int
+	read := func(d *schema.ResourceData, meta interface{}) error {
+		reads++
+
+		switch reads {
+		case 1:
+			// Read functions either remove the resource from the state...
+			d.SetId("")
+			return nil
+		case 2:
+			// ...or return the error as is.
+			return errors.NewNotFound("not found")
+		}
+
+		return d.Set("name", "found")
+	}
+
+	require.NoError(t, resourceReadAfterCreate(read, d, nil, time.Now()))
+	require.Equal(t, 3, reads)
+	require.Equal(t, "123", d.Id())
+	require.Equal(t, "found", d.Get("name"))
+}
+
+func TestResourceReadAfterCreate_OtherErrors(t *testing.T) {
+	d := testResourceReadAfterCreateData(t)
+
+	var reads int
+	read := func(d *schema.ResourceData, meta interface{}) error {
+		reads++
+		return fmt.Errorf("internal server error")
+	}
+
+	require.EqualError(t, resourceReadAfterCreate(read, d, nil, time.Now()), "internal server error")
+	require.Equal(t, 1, reads)
+}
+
+func TestResourceReadAfterCreate_CreateTimeout(t *testing.T) {
+	d := testResourceReadAfterCreateData(t)
+
+	var reads int
+	read := func(d *schema.ResourceData, meta interface{}) error {
+		reads++
+		return errors.NewNotFound("not found")
+	}
+
+	// Once the create timeout has passed, the read is not retried.
+	started := time.Now().Add(-d.Timeout(schema.TimeoutCreate))
+
+	require.EqualError(t, resourceReadAfterCreate(read, d, nil, started), "not found")
+	require.Equal(t, 1, reads)
+}
+
+func TestResourceImportStateWithCompoundID(t *testing.T) {
+	d := resourceNewRelicNrqlAlertCondition().TestResourceData()
+	d.SetId("123:456:static")
+
+	result, err := resourceImportStateWithCompoundID(nrqlConditionID)(d, nil)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Equal(t, "123:456", d.Id())
+	require.Equal(t, "static", d.Get("type"))
+
+	d = resourceNewRelicAlertPolicy().TestResourceData()
+	d.SetId("123:2520528")
+
+	_, err = resourceImportStateWithCompoundID(alertPolicyID)(d, nil)
+	require.NoError(t, err)
+	require.Equal(t, "123", d.Id())
+	require.Equal(t, 2520528, d.Get("account_id"))
+
+	d = resourceNewRelicAlertCondition().TestResourceData()
+	d.SetId("123")
+
+	_, err = resourceImportStateWithCompoundID(policyConditionID)(d, nil)
+	require.EqualError(t, err, `invalid ID "123": expected <policy_id>:<condition_id>`)
+}
+
+func TestParseImportNames(t *testing.T) {
+	names, err := parseImportNames("123:456", policyConditionNames)
+	require.NoError(t, err)
+	require.Nil(t, names)
+
+	names, err = parseImportNames("name:Checkout", []string{"name"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Checkout"}, names)
+
+	names, err = parseImportNames("policy:Checkout/API/condition:High error rate", policyConditionNames)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Checkout/API", "High error rate"}, names)
+
+	_, err = parseImportNames("policy:Checkout", policyConditionNames)
+	require.EqualError(t, err, `invalid ID "policy:Checkout": expected policy:<name>/condition:<name>`)
+
+	_, err = parseImportNames("policy:Checkout/condition:", policyConditionNames)
+	require.EqualError(t, err, `invalid ID "policy:Checkout/condition:": condition name must not be empty`)
+}
+
+func TestImportMatch(t *testing.T) {
+	id, err := importMatch("alert policy", "Checkout", []string{"123"})
+	require.NoError(t, err)
+	require.Equal(t, "123", id)
+
+	_, err = importMatch("alert policy", "Checkout", []string{})
+	require.EqualError(t, err, `no alert policy named "Checkout" was found`)
+
+	_, err = importMatch("alert policy", "Checkout", []string{"123", "456"})
+	require.EqualError(t, err, `more than one alert policy is named "Checkout" (IDs 123, 456), import it by ID instead`)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
 )
 
@@ -46,6 +47,8 @@ var alertChannelTypes = map[string][]string{
 	},
 }
 
+var alertChannelID = integerID("channel_id")
+
 func resourceNewRelicAlertChannel() *schema.Resource {
 	validAlertChannelTypes := make([]string, 0, len(alertChannelTypes))
 	for k := range alertChannelTypes {
@@ -58,7 +61,11 @@ func resourceNewRelicAlertChannel() *schema.Resource {
 		// Update: Not currently supported in API
 		Delete: resourceNewRelicAlertChannelDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByName(alertChannelID, []string{"name"}, importAlertChannelByName),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -256,7 +263,12 @@ func resourceNewRelicAlertChannel() *schema.Resource {
 }
 
 func resourceNewRelicAlertChannelCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	channel, err := expandAlertChannel(d)
 	if err != nil {
 		return err
@@ -275,16 +287,22 @@ func resourceNewRelicAlertChannelCreate(d *schema.ResourceData, meta interface{}
 }
 
 func resourceNewRelicAlertChannelRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	id, err := strconv.ParseInt(d.Id(), 10, 32)
+	ids, err := alertChannelID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
+	id := ids.Int("channel_id")
+
 	log.Printf("[INFO] Reading New Relic alert channel %v", id)
 
-	channel, err := client.Alerts.GetChannel(int(id))
+	channel, err := client.Alerts.GetChannel(id)
 	if err != nil {
 		if _, ok := err.(*errors.NotFound); ok {
 			d.SetId("")
@@ -298,18 +316,40 @@ func resourceNewRelicAlertChannelRead(d *schema.ResourceData, meta interface{})
 }
 
 func resourceNewRelicAlertChannelDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	id, err := strconv.ParseInt(d.Id(), 10, 32)
+	ids, err := alertChannelID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
+	id := ids.Int("channel_id")
+
 	log.Printf("[INFO] Deleting New Relic alert channel %v", id)
 
-	if _, err := client.Alerts.DeleteChannel(int(id)); err != nil {
+	if _, err := client.Alerts.DeleteChannel(id); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func importAlertChannelByName(client *nr.NewRelic, providerConfig *ProviderConfig, d *schema.ResourceData, names []string) (string, error) {
+	channels, err := providerConfig.listAlertChannels(client)
+	if err != nil {
+		return "", err
+	}
+
+	ids := []string{}
+	for _, c := range channels {
+		if c.Name == names[0] {
+			ids = append(ids, strconv.Itoa(c.ID))
+		}
+	}
+
+	return importMatch("alert channel", names[0], ids)
+}
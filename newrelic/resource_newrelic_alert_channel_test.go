@@ -89,6 +89,13 @@ func TestAccNewRelicAlertChannel_Basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			// Test: Import by name
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "name:" + rNameUpdated,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
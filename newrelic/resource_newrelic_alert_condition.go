@@ -3,10 +3,12 @@ package newrelic
 import (
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
 )
 
@@ -80,7 +82,12 @@ func resourceNewRelicAlertCondition() *schema.Resource {
 		Update: resourceNewRelicAlertConditionUpdate,
 		Delete: resourceNewRelicAlertConditionDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByName(policyConditionID, policyConditionNames, importAlertConditionByName("alert condition", listAlertConditionIDs)),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"policy_id": {
@@ -199,7 +206,12 @@ func resourceNewRelicAlertCondition() *schema.Resource {
 }
 
 func resourceNewRelicAlertConditionCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	condition, err := expandAlertCondition(d)
 	if err != nil {
 		return err
@@ -214,23 +226,27 @@ func resourceNewRelicAlertConditionCreate(d *schema.ResourceData, meta interface
 		return err
 	}
 
-	d.SetId(serializeIDs([]int{policyID, condition.ID}))
+	d.SetId(policyConditionID.New(policyID, condition.ID))
 
 	return nil
 }
 
 func resourceNewRelicAlertConditionRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Reading New Relic alert condition %s", d.Id())
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	policyID := ids[0]
-	id := ids[1]
+	policyID := ids.Int("policy_id")
+	id := ids.Int("condition_id")
 
 	_, err = client.Alerts.GetPolicy(policyID)
 	if err != nil {
@@ -258,19 +274,24 @@ func resourceNewRelicAlertConditionRead(d *schema.ResourceData, meta interface{}
 }
 
 func resourceNewRelicAlertConditionUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	condition, err := expandAlertCondition(d)
 	if err != nil {
 		return err
 	}
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	policyID := ids[0]
-	id := ids[1]
+	policyID := ids.Int("policy_id")
+	id := ids.Int("condition_id")
 	condition.ID = id
 
 	log.Printf("[INFO] Updating New Relic alert condition %d", id)
@@ -286,14 +307,18 @@ func resourceNewRelicAlertConditionUpdate(d *schema.ResourceData, meta interface
 }
 
 func resourceNewRelicAlertConditionDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	id := ids[1]
+	id := ids.Int("condition_id")
 
 	log.Printf("[INFO] Deleting New Relic alert condition %d", id)
 
@@ -304,3 +329,19 @@ func resourceNewRelicAlertConditionDelete(d *schema.ResourceData, meta interface
 
 	return nil
 }
+
+func listAlertConditionIDs(client *nr.NewRelic, providerConfig *ProviderConfig, policyID int, name string) ([]string, error) {
+	conditions, err := client.Alerts.ListConditions(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, c := range conditions {
+		if c.Name == name {
+			ids = append(ids, strconv.Itoa(c.ID))
+		}
+	}
+
+	return ids, nil
+}
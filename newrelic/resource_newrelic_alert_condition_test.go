@@ -224,13 +224,13 @@ func testAccCheckNewRelicAlertConditionDestroy(s *terraform.State) error {
 			continue
 		}
 
-		ids, err := parseIDs(r.Primary.ID, 2)
+		ids, err := policyConditionID.Parse(r.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := ids[0]
-		id := ids[1]
+		policyID := ids.Int("policy_id")
+		id := ids.Int("condition_id")
 
 		_, err = client.Alerts.GetCondition(policyID, id)
 		if err == nil {
@@ -253,13 +253,13 @@ func testAccCheckNewRelicAlertConditionExists(n string) resource.TestCheckFunc {
 
 		client := testAccProvider.Meta().(*ProviderConfig).NewClient
 
-		ids, err := parseIDs(rs.Primary.ID, 2)
+		ids, err := policyConditionID.Parse(rs.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := ids[0]
-		id := ids[1]
+		policyID := ids.Int("policy_id")
+		id := ids.Int("condition_id")
 
 		found, err := client.Alerts.GetCondition(policyID, id)
 		if err != nil {
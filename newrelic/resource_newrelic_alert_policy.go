@@ -2,7 +2,6 @@ package newrelic
 
 import (
 	"errors"
-	"fmt"
 	"log"
 	"strconv"
 
@@ -11,8 +10,30 @@ import (
 	"github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+
+	"github.com/terraform-providers/terraform-provider-newrelic/internal/compoundid"
 )
 
+// The ID of an alert policy. Policies can also be imported with the ID of
+// their account appended to the ID.
+var alertPolicyID = &compoundid.Schema{
+	Formats: []compoundid.Format{
+		{
+			Version: 1,
+			Parts: []compoundid.Part{
+				{Name: "policy_id", Kind: compoundid.Int},
+			},
+		},
+		{
+			Version: 1,
+			Parts: []compoundid.Part{
+				{Name: "policy_id", Kind: compoundid.Int},
+				{Name: "account_id", Kind: compoundid.Int, Attribute: true},
+			},
+		},
+	},
+}
+
 func resourceNewRelicAlertPolicy() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceNewRelicAlertPolicyCreate,
@@ -20,7 +41,12 @@ func resourceNewRelicAlertPolicy() *schema.Resource {
 		Update: resourceNewRelicAlertPolicyUpdate,
 		Delete: resourceNewRelicAlertPolicyDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceImportStateWithMetadata(1, "account_id"),
+			State: resourceImportStateByName(alertPolicyID, []string{"name"}, importAlertPolicyByName),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -51,6 +77,7 @@ func resourceNewRelicAlertPolicy() *schema.Resource {
 				ForceNew:    true,
 				Description: "An array of channel IDs (integers) to assign to the policy. Adding or removing channel IDs from this array will result in a new alert policy resource being created and the old one being destroyed. Also note that channel IDs cannot be imported via terraform import.",
 			},
+			"deletion_protection": deletionProtectionSchema(),
 		},
 	}
 }
@@ -62,7 +89,12 @@ func resourceNewRelicAlertPolicyCreate(d *schema.ResourceData, meta interface{})
 		return errors.New("err: NerdGraph support not present, but required for Create")
 	}
 
-	client := providerConfig.NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	accountID := selectAccountID(providerConfig, d)
 
 	policy := alerts.AlertsPolicyInput{}
@@ -92,7 +124,7 @@ func resourceNewRelicAlertPolicyCreate(d *schema.ResourceData, meta interface{})
 
 	if len(channels) > 0 {
 		channelIDs := expandAlertChannelIDs(channels)
-		matchedChannelIDs, err := findExistingChannelIDs(client, channelIDs)
+		matchedChannelIDs, err := findExistingChannelIDs(providerConfig, client, channelIDs)
 		if err != nil {
 			return err
 		}
@@ -104,7 +136,10 @@ func resourceNewRelicAlertPolicyCreate(d *schema.ResourceData, meta interface{})
 			return err
 		}
 
+		unlock := providerConfig.lockAlertPolicy(createResultID)
 		_, err = client.Alerts.UpdatePolicyChannels(createResultID, matchedChannelIDs)
+		unlock()
+
 		if err != nil {
 			return err
 		}
@@ -120,26 +155,22 @@ func resourceNewRelicAlertPolicyRead(d *schema.ResourceData, meta interface{}) e
 		return errors.New("err: NerdGraph support not present, but required for Read")
 	}
 
-	client := providerConfig.NewClient
-
-	ids, err := parseHashedIDs(d.Id())
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
 	if err != nil {
 		return err
 	}
+	defer cancel()
 
-	var accountID int
-	var policyID int
-
-	if len(ids) == 1 {
-		policyID = ids[0]
-		accountID = selectAccountID(providerConfig, d)
+	ids, err := alertPolicyID.Parse(d.Id())
+	if err != nil {
+		return err
+	}
 
-	} else if len(ids) == 2 {
-		policyID = ids[0]
-		accountID = ids[1]
+	policyID := ids.Int("policy_id")
+	accountID := selectAccountID(providerConfig, d)
 
-	} else {
-		return fmt.Errorf("unhandled id format %s", d.Id())
+	if ids.Has("account_id") {
+		accountID = ids.Int("account_id")
 	}
 
 	log.Printf("[INFO] Reading New Relic alert policy %d from account %d", policyID, accountID)
@@ -167,7 +198,11 @@ func resourceNewRelicAlertPolicyUpdate(d *schema.ResourceData, meta interface{})
 		return errors.New("err: NerdGraph support not present, but required for Update")
 	}
 
-	client := providerConfig.NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	accountID := selectAccountID(providerConfig, d)
 
@@ -200,13 +235,26 @@ func resourceNewRelicAlertPolicyDelete(d *schema.ResourceData, meta interface{})
 		return errors.New("err: NerdGraph support not present, but required for Delete")
 	}
 
-	client := providerConfig.NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	accountID := selectAccountID(providerConfig, d)
 
 	log.Printf("[INFO] Deleting New Relic alert policy %s from account %d", d.Id(), accountID)
 
-	_, err := client.Alerts.DeletePolicyMutation(accountID, d.Id())
+	policyID, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// Channels are not added to a policy while it is being deleted.
+	unlock := providerConfig.lockAlertPolicy(policyID)
+	defer unlock()
+
+	_, err = client.Alerts.DeletePolicyMutation(accountID, d.Id())
 	if err != nil {
 		return err
 	}
@@ -214,9 +262,8 @@ func resourceNewRelicAlertPolicyDelete(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
-func findExistingChannelIDs(client *newrelic.NewRelic, channelIDs []int) ([]int, error) {
-	channels, err := client.Alerts.ListChannels()
-
+func findExistingChannelIDs(providerConfig *ProviderConfig, client *newrelic.NewRelic, channelIDs []int) ([]int, error) {
+	channels, err := providerConfig.listAlertChannels(client)
 	if err != nil {
 		return nil, err
 	}
@@ -233,3 +280,52 @@ func findExistingChannelIDs(client *newrelic.NewRelic, channelIDs []int) ([]int,
 
 	return matched, nil
 }
+
+func importAlertPolicyByName(client *newrelic.NewRelic, providerConfig *ProviderConfig, d *schema.ResourceData, names []string) (string, error) {
+	accountID := selectAccountID(providerConfig, d)
+
+	policyID, err := findAlertPolicyIDByName(client, providerConfig, accountID, names[0])
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(policyID), nil
+}
+
+// Returns the ID of the only alert policy with the given name, using NerdGraph
+// when it is configured and the REST API otherwise.
+func findAlertPolicyIDByName(client *newrelic.NewRelic, providerConfig *ProviderConfig, accountID int, name string) (int, error) {
+	ids := []string{}
+
+	if providerConfig.hasNerdGraphCredentials() {
+		policies, err := providerConfig.searchAlertPolicies(client, accountID)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, p := range policies {
+			if p.Name == name {
+				ids = append(ids, p.ID)
+			}
+		}
+	} else {
+		// The REST API filters policies by names containing the one given.
+		policies, err := providerConfig.listAlertPolicies(client, name)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, p := range policies {
+			if p.Name == name {
+				ids = append(ids, strconv.Itoa(p.ID))
+			}
+		}
+	}
+
+	id, err := importMatch("alert policy", name, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(id)
+}
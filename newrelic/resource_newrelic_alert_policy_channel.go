@@ -2,13 +2,30 @@ package newrelic
 
 import (
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
+
+	"github.com/terraform-providers/terraform-provider-newrelic/internal/compoundid"
 )
 
+// The ID of the channels of an alert policy, which holds the IDs of every
+// channel managed by the resource.
+var alertPolicyChannelID = &compoundid.Schema{
+	Formats: []compoundid.Format{
+		{
+			Version: 1,
+			Parts: []compoundid.Part{
+				{Name: "policy_id", Kind: compoundid.Int},
+				{Name: "channel_id", Kind: compoundid.Int, Repeated: true},
+			},
+		},
+	},
+}
+
 func resourceNewRelicAlertPolicyChannel() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceNewRelicAlertPolicyChannelCreate,
@@ -16,7 +33,11 @@ func resourceNewRelicAlertPolicyChannel() *schema.Resource {
 		// Update: Not currently supported in API
 		Delete: resourceNewRelicAlertPolicyChannelDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateWithCompoundID(alertPolicyChannelID),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"policy_id": {
@@ -49,7 +70,14 @@ func resourceNewRelicAlertPolicyChannel() *schema.Resource {
 }
 
 func resourceNewRelicAlertPolicyChannelCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	started := time.Now()
+
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	policyChannels, err := expandAlertPolicyChannels(d)
 
 	if err != nil {
@@ -58,17 +86,18 @@ func resourceNewRelicAlertPolicyChannelCreate(d *schema.ResourceData, meta inter
 
 	sortIntegerSlice(policyChannels.ChannelIDs)
 
-	serializedID := serializeIDs(append(
-		[]int{policyChannels.ID},
-		policyChannels.ChannelIDs...,
-	))
+	serializedID := alertPolicyChannelID.New(policyChannels.ID, policyChannels.ChannelIDs)
 
 	log.Printf("[INFO] Creating New Relic alert policy channel %s", serializedID)
 
+	// The policy is only locked while it changes, as reading it back may
+	// be retried for a while.
+	unlock := meta.(*ProviderConfig).lockAlertPolicy(policyChannels.ID)
 	_, err = client.Alerts.UpdatePolicyChannels(
 		policyChannels.ID,
 		policyChannels.ChannelIDs,
 	)
+	unlock()
 
 	if err != nil {
 		return err
@@ -76,25 +105,29 @@ func resourceNewRelicAlertPolicyChannelCreate(d *schema.ResourceData, meta inter
 
 	d.SetId(serializedID)
 
-	return resourceNewRelicAlertPolicyChannelRead(d, meta)
+	return resourceReadAfterCreate(resourceNewRelicAlertPolicyChannelRead, d, meta, started)
 }
 
 func resourceNewRelicAlertPolicyChannelRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	ids, err := parseHashedIDs(d.Id())
+	ids, err := alertPolicyChannelID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	policyID := ids[0]
-	parsedChannelIDs := ids[1:]
+	policyID := ids.Int("policy_id")
+	parsedChannelIDs := ids.Ints("channel_id")
 
 	sortIntegerSlice(parsedChannelIDs)
 
 	log.Printf("[INFO] Reading New Relic alert policy channel %s", d.Id())
 
-	exists, err := policyChannelsExist(client, policyID, parsedChannelIDs)
+	exists, err := policyChannelsExist(meta.(*ProviderConfig), client, policyID, parsedChannelIDs)
 
 	if err != nil {
 		return err
@@ -109,19 +142,26 @@ func resourceNewRelicAlertPolicyChannelRead(d *schema.ResourceData, meta interfa
 }
 
 func resourceNewRelicAlertPolicyChannelDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	ids, err := parseHashedIDs(d.Id())
+	ids, err := alertPolicyChannelID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	policyID := ids[0]
-	channelIDs := ids[1:]
+	policyID := ids.Int("policy_id")
+	channelIDs := ids.Ints("channel_id")
 
 	log.Printf("[INFO] Deleting New Relic alert policy channel %s", d.Id())
 
-	exists, err := policyChannelsExist(client, policyID, channelIDs)
+	unlock := meta.(*ProviderConfig).lockAlertPolicy(policyID)
+	defer unlock()
+
+	exists, err := policyChannelsExist(meta.(*ProviderConfig), client, policyID, channelIDs)
 	if err != nil {
 		return err
 	}
@@ -141,12 +181,12 @@ func resourceNewRelicAlertPolicyChannelDelete(d *schema.ResourceData, meta inter
 }
 
 func policyChannelsExist(
+	providerConfig *ProviderConfig,
 	client *newrelic.NewRelic,
 	policyID int,
 	channelIDs []int,
 ) (bool, error) {
-	channels, err := client.Alerts.ListChannels()
-
+	channels, err := providerConfig.listAlertChannels(client)
 	if err != nil {
 		return false, err
 	}
@@ -126,15 +126,15 @@ func testAccCheckNewRelicAlertPolicyChannelDestroy(s *terraform.State) error {
 			continue
 		}
 
-		ids, err := parseHashedIDs(r.Primary.ID)
+		ids, err := alertPolicyChannelID.Parse(r.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := ids[0]
-		channelIDs := ids[1:]
+		policyID := ids.Int("policy_id")
+		channelIDs := ids.Ints("channel_id")
 
-		exists, err := policyChannelsExist(client, policyID, channelIDs)
+		exists, err := policyChannelsExist(testAccProvider.Meta().(*ProviderConfig), client, policyID, channelIDs)
 		if err != nil {
 			return err
 		}
@@ -158,15 +158,15 @@ func testAccCheckNewRelicAlertPolicyChannelExists(n string) resource.TestCheckFu
 
 		client := testAccProvider.Meta().(*ProviderConfig).NewClient
 
-		ids, err := parseHashedIDs(rs.Primary.ID)
+		ids, err := alertPolicyChannelID.Parse(rs.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := ids[0]
-		channelIDs := ids[1:]
+		policyID := ids.Int("policy_id")
+		channelIDs := ids.Ints("channel_id")
 
-		exists, err := policyChannelsExist(client, policyID, channelIDs)
+		exists, err := policyChannelsExist(testAccProvider.Meta().(*ProviderConfig), client, policyID, channelIDs)
 		if err != nil {
 			return err
 		}
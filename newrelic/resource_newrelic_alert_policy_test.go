@@ -41,6 +41,13 @@ func TestAccNewRelicAlertPolicy_Basic(t *testing.T) {
 				ImportStateVerify: true,
 				ResourceName:      resourceName,
 			},
+			// Test: Import by name
+			{
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("name:tf-test-updated-%s", rName),
+				ImportStateVerify: true,
+				ResourceName:      resourceName,
+			},
 		},
 	})
 }
@@ -117,6 +124,49 @@ func TestAccNewRelicAlertPolicy_WithChannels(t *testing.T) {
 	})
 }
 
+func TestAccNewRelicAlertPolicy_DeletionProtection(t *testing.T) {
+	resourceName := "newrelic_alert_policy.foo"
+	rName := acctest.RandString(5)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckNewRelicAlertPolicyDestroy,
+		Steps: []resource.TestStep{
+			// Test: Create protected
+			{
+				Config: testAccNewRelicAlertPolicyConfigDeletionProtection(rName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNewRelicAlertPolicyExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "deletion_protection", "true"),
+				),
+			},
+			// Test: Removing the policy is refused
+			{
+				Config: `
+resource "newrelic_alert_channel" "foo" {
+  name = "tf-test-deletion-protection"
+  type = "email"
+
+  config {
+    recipients = "foo@example.com"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile("deletion_protection is enabled"),
+			},
+			// Test: Unprotect
+			{
+				Config: testAccNewRelicAlertPolicyConfigDeletionProtection(rName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNewRelicAlertPolicyExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckNewRelicAlertPolicyDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*ProviderConfig).NewClient
 	for _, r := range s.RootModule().Resources {
@@ -124,12 +174,12 @@ func testAccCheckNewRelicAlertPolicyDestroy(s *terraform.State) error {
 			continue
 		}
 
-		idParts, err := parseHashedIDs(r.Primary.ID)
+		ids, err := alertPolicyID.Parse(r.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := strconv.Itoa(idParts[0])
+		policyID := strconv.Itoa(ids.Int("policy_id"))
 
 		_, err = client.Alerts.QueryPolicy(testAccountID, policyID)
 
@@ -154,12 +204,12 @@ func testAccCheckNewRelicAlertPolicyExists(n string) resource.TestCheckFunc {
 
 		client := testAccProvider.Meta().(*ProviderConfig).NewClient
 
-		idParts, err := parseHashedIDs(rs.Primary.ID)
+		ids, err := alertPolicyID.Parse(rs.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := strconv.Itoa(idParts[0])
+		policyID := strconv.Itoa(ids.Int("policy_id"))
 
 		found, err := client.Alerts.QueryPolicy(testAccountID, policyID)
 		if err != nil {
@@ -234,3 +284,12 @@ resource "newrelic_alert_policy" "foo" {
 }
 `, name)
 }
+
+func testAccNewRelicAlertPolicyConfigDeletionProtection(name string, deletionProtection bool) string {
+	return fmt.Sprintf(`
+resource "newrelic_alert_policy" "foo" {
+  name                = "tf-test-%s"
+  deletion_protection = %t
+}
+`, name, deletionProtection)
+}
@@ -7,8 +7,24 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/newrelic/newrelic-client-go/pkg/apm"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
+
+	"github.com/terraform-providers/terraform-provider-newrelic/internal/compoundid"
 )
 
+// The ID of an application label, which is also the key of the label in the
+// REST API.
+var applicationLabelID = &compoundid.Schema{
+	Formats: []compoundid.Format{
+		{
+			Version: 1,
+			Parts: []compoundid.Part{
+				{Name: "category", Kind: compoundid.String},
+				{Name: "name", Kind: compoundid.String},
+			},
+		},
+	},
+}
+
 func resourceNewRelicApplicationLabel() *schema.Resource {
 	return &schema.Resource{
 		DeprecationMessage: "The `newrelic_application_label` resource is deprecated. Use at your own risk.",
@@ -17,7 +33,11 @@ func resourceNewRelicApplicationLabel() *schema.Resource {
 		Read:               resourceNewRelicApplicationLabelRead,
 		Delete:             resourceNewRelicApplicationLabelDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateWithCompoundID(applicationLabelID),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"category": {
@@ -64,17 +84,22 @@ func resourceNewRelicApplicationLabel() *schema.Resource {
 }
 
 func resourceNewRelicApplicationLabelCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	label := expandApplicationLabel(d)
 
 	log.Printf("[INFO] Creating New Relic Application label %s:%s", label.Category, label.Name)
 
-	_, err := client.APM.CreateLabel(label)
+	_, err = client.APM.CreateLabel(label)
 	if err != nil {
 		return err
 	}
 
-	d.SetId(strings.Join([]string{label.Category, label.Name}, ":"))
+	d.SetId(applicationLabelID.New(label.Category, label.Name))
 
 	return nil
 }
@@ -96,7 +121,12 @@ func resourceNewRelicApplicationLabelUpdate(d *schema.ResourceData, meta interfa
 }
 
 func resourceNewRelicApplicationLabelRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	key := d.Id()
 	log.Printf("[INFO] Reading New Relic Application label %s", key)
 
@@ -119,13 +149,17 @@ func resourceNewRelicApplicationLabelRead(d *schema.ResourceData, meta interface
 }
 
 func resourceNewRelicApplicationLabelDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	key := d.Id()
 
 	log.Printf("[INFO] Deleting New Relic label %s", key)
 
-	_, err := client.APM.DeleteLabel(key)
+	_, err = client.APM.DeleteLabel(key)
 	if err != nil {
 		return err
 	}
@@ -9,6 +9,8 @@ import (
 	"github.com/newrelic/newrelic-client-go/pkg/apm"
 )
 
+var applicationID = integerID("application_id")
+
 func resourceNewRelicApplicationSettings() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceNewRelicApplicationSettingsCreate,
@@ -16,7 +18,11 @@ func resourceNewRelicApplicationSettings() *schema.Resource {
 		Update: resourceNewRelicApplicationSettingsUpdate,
 		Delete: resourceNewRelicApplicationSettingsDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateWithCompoundID(applicationID),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -35,20 +41,22 @@ func resourceNewRelicApplicationSettings() *schema.Resource {
 				Type:     schema.TypeBool,
 				Required: true,
 			},
+			"tags":     entityTagsSchema(),
+			"tags_all": entityTagsAllSchema(),
 		},
 	}
 }
 
 func resourceNewRelicApplicationSettingsCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	userApp := expandApplication(d)
 
-	listParams := apm.ListApplicationsParams{
-		Name: userApp.Name,
-	}
-
-	result, err := client.APM.ListApplications(&listParams)
+	result, err := meta.(*ProviderConfig).listApplications(client, userApp.Name)
 	if err != nil {
 		return err
 	}
@@ -70,7 +78,11 @@ func resourceNewRelicApplicationSettingsCreate(d *schema.ResourceData, meta inte
 }
 
 func resourceNewRelicApplicationSettingsRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	userApp := expandApplication(d)
 	log.Printf("[INFO] Reading New Relic application %+v", userApp)
@@ -86,7 +98,11 @@ func resourceNewRelicApplicationSettingsRead(d *schema.ResourceData, meta interf
 }
 
 func resourceNewRelicApplicationSettingsUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	userApp := expandApplication(d)
 
@@ -97,7 +113,7 @@ func resourceNewRelicApplicationSettingsUpdate(d *schema.ResourceData, meta inte
 
 	log.Printf("[INFO] Updating New Relic application %+v with params: %+v", userApp, updateParams)
 
-	_, err := client.APM.UpdateApplication(userApp.ID, updateParams)
+	_, err = client.APM.UpdateApplication(userApp.ID, updateParams)
 	if err != nil {
 		return err
 	}
@@ -105,6 +105,11 @@ func testAccCheckNewRelicApplicationExists(n string) resource.TestCheckFunc {
 }
 
 func testPreCheck(t *testing.T) {
+	if testAccMockAPIServer != nil {
+		testAccMockAPIServer.reportApplication(testExpectedApplicationName)
+		return
+	}
+
 	if v := os.Getenv("NEWRELIC_API_KEY"); v == "" {
 		t.Fatal("NEWRELIC_API_KEY must be set for acceptance tests")
 	}
@@ -3,9 +3,12 @@ package newrelic
 import (
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/dashboards"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
 )
 
@@ -82,6 +85,8 @@ var (
 	}
 )
 
+var dashboardID = integerID("dashboard_id")
+
 func resourceNewRelicDashboard() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceNewRelicDashboardCreate,
@@ -89,7 +94,12 @@ func resourceNewRelicDashboard() *schema.Resource {
 		Update: resourceNewRelicDashboardUpdate,
 		Delete: resourceNewRelicDashboardDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByName(dashboardID, []string{"name"}, importDashboardByName),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"title": {
@@ -203,9 +213,10 @@ func resourceNewRelicDashboard() *schema.Resource {
 							Description: "Description of the widget.",
 						},
 						"nrql": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Valid NRQL query string.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "Valid NRQL query string.",
+							ValidateFunc: validateNrql,
 						},
 						"source": {
 							Type:        schema.TypeString,
@@ -330,12 +341,22 @@ func resourceNewRelicDashboard() *schema.Resource {
 					},
 				},
 			},
+			"deletion_protection": deletionProtectionSchema(),
+			"tags":                entityTagsSchema(),
+			"tags_all":            entityTagsAllSchema(),
 		},
 	}
 }
 
 func resourceNewRelicDashboardCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	started := time.Now()
+
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	dashboard, err := expandDashboard(d)
 	if err != nil {
 		return err
@@ -350,20 +371,24 @@ func resourceNewRelicDashboardCreate(d *schema.ResourceData, meta interface{}) e
 
 	d.SetId(strconv.Itoa(dashboard.ID))
 
-	return resourceNewRelicDashboardRead(d, meta)
+	return resourceReadAfterCreate(resourceNewRelicDashboardRead, d, meta, started)
 }
 
 func resourceNewRelicDashboardRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Reading New Relic dashboard %s", d.Id())
 
-	dashboardID, err := strconv.Atoi(d.Id())
+	ids, err := dashboardID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	dashboard, err := client.Dashboards.GetDashboard(dashboardID)
+	dashboard, err := client.Dashboards.GetDashboard(ids.Int("dashboard_id"))
 	if err != nil {
 		if _, ok := err.(*errors.NotFound); ok {
 			d.SetId("")
@@ -377,17 +402,24 @@ func resourceNewRelicDashboardRead(d *schema.ResourceData, meta interface{}) err
 }
 
 func resourceNewRelicDashboardUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	dashboard, err := expandDashboard(d)
 	if err != nil {
 		return err
 	}
 
-	id, err := strconv.Atoi(d.Id())
+	ids, err := dashboardID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
+	id := ids.Int("dashboard_id")
+
 	dashboard.ID = id
 	log.Printf("[INFO] Updating New Relic dashboard %d", id)
 
@@ -400,13 +432,19 @@ func resourceNewRelicDashboardUpdate(d *schema.ResourceData, meta interface{}) e
 }
 
 func resourceNewRelicDashboardDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	id, err := strconv.Atoi(d.Id())
+	ids, err := dashboardID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
+	id := ids.Int("dashboard_id")
+
 	log.Printf("[INFO] Deleting New Relic dashboard %v", id)
 
 	if _, err := client.Dashboards.DeleteDashboard(id); err != nil {
@@ -418,3 +456,20 @@ func resourceNewRelicDashboardDelete(d *schema.ResourceData, meta interface{}) e
 
 	return nil
 }
+
+func importDashboardByName(client *nr.NewRelic, providerConfig *ProviderConfig, d *schema.ResourceData, names []string) (string, error) {
+	// The API filters dashboards by titles containing the one given.
+	list, err := client.Dashboards.ListDashboards(&dashboards.ListDashboardsParams{Title: names[0]})
+	if err != nil {
+		return "", err
+	}
+
+	ids := []string{}
+	for _, db := range list {
+		if db.Title == names[0] {
+			ids = append(ids, strconv.Itoa(db.ID))
+		}
+	}
+
+	return importMatch("dashboard", names[0], ids)
+}
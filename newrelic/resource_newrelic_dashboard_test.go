@@ -35,6 +35,14 @@ func TestAccNewRelicDashboard_Basic(t *testing.T) {
 				// grid_column_count is not returned in the GET response
 				ImportStateVerifyIgnore: []string{"grid_column_count"},
 			},
+			// Import by name
+			{
+				ResourceName:            "newrelic_dashboard.foo",
+				ImportState:             true,
+				ImportStateId:           "name:" + rName,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"grid_column_count"},
+			},
 		},
 	})
 }
@@ -2,10 +2,13 @@ package newrelic
 
 import (
 	"log"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
 )
 
@@ -27,6 +30,11 @@ var thresholdConditionTypes = map[string][]string{
 // thresholdSchema returns the schema to use for threshold.
 func thresholdSchema() *schema.Resource {
 	return &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 		Schema: map[string]*schema.Schema{
 			"value": {
 				Type:     schema.TypeFloat,
@@ -60,7 +68,7 @@ func resourceNewRelicInfraAlertCondition() *schema.Resource {
 		Update: resourceNewRelicInfraAlertConditionUpdate,
 		Delete: resourceNewRelicInfraAlertConditionDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByName(policyConditionID, policyConditionNames, importAlertConditionByName("infrastructure alert condition", listInfraAlertConditionIDs)),
 		},
 		Schema: map[string]*schema.Schema{
 			"policy_id": {
@@ -171,7 +179,14 @@ func resourceNewRelicInfraAlertCondition() *schema.Resource {
 }
 
 func resourceNewRelicInfraAlertConditionCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	started := time.Now()
+
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	condition, err := expandInfraAlertCondition(d)
 
 	if err != nil {
@@ -186,23 +201,27 @@ func resourceNewRelicInfraAlertConditionCreate(d *schema.ResourceData, meta inte
 		return err
 	}
 
-	d.SetId(serializeIDs([]int{condition.PolicyID, condition.ID}))
+	d.SetId(policyConditionID.New(condition.PolicyID, condition.ID))
 
-	return resourceNewRelicInfraAlertConditionRead(d, meta)
+	return resourceReadAfterCreate(resourceNewRelicInfraAlertConditionRead, d, meta, started)
 }
 
 func resourceNewRelicInfraAlertConditionRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Reading New Relic Infra alert condition %s", d.Id())
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	policyID := ids[0]
-	id := ids[1]
+	policyID := ids.Int("policy_id")
+	id := ids.Int("condition_id")
 
 	_, err = client.Alerts.GetPolicy(policyID)
 	if err != nil {
@@ -227,20 +246,25 @@ func resourceNewRelicInfraAlertConditionRead(d *schema.ResourceData, meta interf
 }
 
 func resourceNewRelicInfraAlertConditionUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	condition, err := expandInfraAlertCondition(d)
 
 	if err != nil {
 		return err
 	}
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	policyID := ids[0]
-	id := ids[1]
+	policyID := ids.Int("policy_id")
+	id := ids.Int("condition_id")
 
 	condition.PolicyID = policyID
 	condition.ID = id
@@ -256,14 +280,18 @@ func resourceNewRelicInfraAlertConditionUpdate(d *schema.ResourceData, meta inte
 }
 
 func resourceNewRelicInfraAlertConditionDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	id := ids[1]
+	id := ids.Int("condition_id")
 
 	log.Printf("[INFO] Deleting New Relic Infra alert condition %d", id)
 
@@ -273,3 +301,19 @@ func resourceNewRelicInfraAlertConditionDelete(d *schema.ResourceData, meta inte
 
 	return nil
 }
+
+func listInfraAlertConditionIDs(client *nr.NewRelic, providerConfig *ProviderConfig, policyID int, name string) ([]string, error) {
+	conditions, err := client.Alerts.ListInfrastructureConditions(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, c := range conditions {
+		if c.Name == name {
+			ids = append(ids, strconv.Itoa(c.ID))
+		}
+	}
+
+	return ids, nil
+}
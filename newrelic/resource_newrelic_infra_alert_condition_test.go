@@ -257,12 +257,12 @@ func testAccCheckNewRelicInfraAlertConditionDestroy(s *terraform.State) error {
 			continue
 		}
 
-		ids, err := parseIDs(r.Primary.ID, 2)
+		ids, err := policyConditionID.Parse(r.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		id := ids[1]
+		id := ids.Int("condition_id")
 
 		_, err = client.Alerts.GetInfrastructureCondition(id)
 		if err == nil {
@@ -285,12 +285,12 @@ func testAccCheckNewRelicInfraAlertConditionExists(n string) resource.TestCheckF
 
 		client := testAccProvider.Meta().(*ProviderConfig).NewClient
 
-		ids, err := parseIDs(rs.Primary.ID, 2)
+		ids, err := policyConditionID.Parse(rs.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		id := ids[1]
+		id := ids.Int("condition_id")
 
 		found, err := client.Alerts.GetInfrastructureCondition(id)
 		if err != nil {
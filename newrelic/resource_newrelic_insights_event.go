@@ -17,6 +17,9 @@ func resourceNewRelicInsightsEvent() *schema.Resource {
 		Read:   schema.Noop,
 		Delete: schema.RemoveFromState,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 		Schema: map[string]*schema.Schema{
 			"event": {
 				Type:     schema.TypeSet,
@@ -117,7 +120,7 @@ func (e *InsightsEvent) MarshalJSON() ([]byte, error) {
 }
 
 func resourceNewRelicInsightsEventCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).InsightsInsertClient
+	providerConfig := meta.(*ProviderConfig)
 	var eventsPayload []*InsightsEvent
 
 	if v, ok := d.GetOkExists("event"); ok {
@@ -162,8 +165,14 @@ func resourceNewRelicInsightsEventCreate(d *schema.ResourceData, meta interface{
 		}
 	}
 
+	client, cancel, err := providerConfig.insightsInsertClient(d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	if err := client.PostEvent(eventsPayload); err != nil {
-		return fmt.Errorf("error occurreed while posting events to Insights: %q", err)
+		return fmt.Errorf("error posting events to Insights: %w", err)
 	}
 
 	d.SetId(fmt.Sprintf("%d", rand.Int()))
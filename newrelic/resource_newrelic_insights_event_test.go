@@ -67,7 +67,11 @@ func testAccCheckNewRelicInsightsEventExists(n string, nrqls []string) resource.
 			return fmt.Errorf("no event ID is set")
 		}
 
-		client := testAccProvider.Meta().(*ProviderConfig).InsightsQueryClient
+		client, cancel, err := testAccProvider.Meta().(*ProviderConfig).insightsQueryClient(time.Minute)
+		if err != nil {
+			return err
+		}
+		defer cancel()
 
 		for _, nrql := range nrqls {
 			resp, err := client.QueryEvents(nrql)
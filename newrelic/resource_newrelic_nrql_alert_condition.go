@@ -6,21 +6,58 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
+
+	"github.com/terraform-providers/terraform-provider-newrelic/internal/compoundid"
+	"github.com/terraform-providers/terraform-provider-newrelic/internal/nrql"
 )
 
+// The ID of an NRQL alert condition. Conditions can also be imported with
+// their type appended to the ID, since it cannot be told from the condition.
+var nrqlConditionID = &compoundid.Schema{
+	Formats: []compoundid.Format{
+		policyConditionID.Current(),
+		{
+			Version: 1,
+			Parts: []compoundid.Part{
+				{Name: "policy_id", Kind: compoundid.Int},
+				{Name: "condition_id", Kind: compoundid.Int},
+				{Name: "type", Kind: compoundid.String, Attribute: true},
+			},
+		},
+	},
+}
+
 func resourceNewRelicNrqlAlertCondition() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceNewRelicNrqlAlertConditionCreate,
 		Read:   resourceNewRelicNrqlAlertConditionRead,
 		Update: resourceNewRelicNrqlAlertConditionUpdate,
 		Delete: resourceNewRelicNrqlAlertConditionDelete,
+		// Rules that span several attributes are checked when planning,
+		// rather than when the condition is sent to the API.
+		CustomizeDiff: resourceNewRelicNrqlAlertConditionCustomizeDiff,
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceNewRelicNrqlAlertConditionV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceNewRelicNrqlAlertConditionStateUpgradeV0,
+				Version: 0,
+			},
+		},
 		Importer: &schema.ResourceImporter{
-			State: resourceImportStateWithMetadata(2, "type"),
+			State: resourceImportStateByName(nrqlConditionID, policyConditionNames, importAlertConditionByName("NRQL alert condition", listNrqlAlertConditionIDs)),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"policy_id": {
@@ -45,7 +82,6 @@ func resourceNewRelicNrqlAlertCondition() *schema.Resource {
 				Default:     true,
 				Description: "Whether or not to enable the alert condition.",
 			},
-			// Note: The "outlier" type does NOT exist in NerdGraph yet
 			"type": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -62,8 +98,9 @@ func resourceNewRelicNrqlAlertCondition() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"query": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateNrqlConditionQuery,
 						},
 						"since_value": {
 							Deprecated:    "use `evaluation_offset` attribute instead",
@@ -87,6 +124,7 @@ func resourceNewRelicNrqlAlertCondition() *schema.Resource {
 						"evaluation_offset": {
 							Type:          schema.TypeInt,
 							Optional:      true,
+							Computed:      true,
 							Description:   "NRQL queries are evaluated in one-minute time windows. The start time depends on the value you provide in the NRQL condition's `evaluation_offset`.",
 							ConflictsWith: []string{"nrql.0.since_value"},
 							ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
@@ -252,6 +290,44 @@ func resourceNewRelicNrqlAlertCondition() *schema.Resource {
 					return strings.EqualFold(old, new) // Case fold this attribute when diffing
 				},
 			},
+			"aggregation_window": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "The duration of the time window used to evaluate the NRQL query, in seconds. Must be between 30 and 900.",
+				ValidateFunc: validation.IntBetween(30, 900),
+			},
+			"fill_option": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Which strategy to use when filling gaps in the signal. Valid values are: 'NONE', 'LAST_VALUE', 'STATIC' (case insensitive).",
+				ValidateFunc: validation.StringInSlice([]string{"NONE", "LAST_VALUE", "STATIC"}, true),
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return strings.EqualFold(old, new) // Case fold this attribute when diffing
+				},
+			},
+			"fill_value": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Description: "The value used to fill gaps in the signal when `fill_option` is 'STATIC'.",
+			},
+			"expiration_duration": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "The amount of time, in seconds, to wait before considering the signal expired. Must be between 30 and 172800.",
+				ValidateFunc: validation.IntBetween(30, 172800),
+			},
+			"open_violation_on_expiration": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Whether to create a new violation to capture that the signal expired.",
+			},
+			"close_violations_on_expiration": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Whether to close all open violations when the signal expires.",
+			},
 			// Baseline ONLY
 			"baseline_direction": {
 				Type:          schema.TypeString,
@@ -267,36 +343,137 @@ func resourceNewRelicNrqlAlertCondition() *schema.Resource {
 	}
 }
 
+// Reports every attribute of a NRQL alert condition that is invalid given the
+// others. Attributes whose values are not known yet are skipped.
+func resourceNewRelicNrqlAlertConditionCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("type") {
+		return nil
+	}
+
+	conditionType := d.Get("type").(string)
+	errs := []string{}
+
+	if conditionType == "baseline" {
+		if _, ok := d.GetOk("baseline_direction"); !ok && d.NewValueKnown("baseline_direction") {
+			errs = append(errs, "`baseline_direction` is required for conditions of type `baseline`")
+		}
+
+		// `value_function` has a default, so only other values can be told
+		// apart from it not being set. Setting it to the default alongside
+		// `baseline_direction` is caught by ConflictsWith.
+		if valueFunction := d.Get("value_function").(string); !strings.EqualFold(valueFunction, "single_value") {
+			errs = append(errs, "`value_function` cannot be set for conditions of type `baseline`")
+		}
+	}
+
+	if conditionType == "outlier" {
+		if _, ok := d.GetOk("expected_groups"); !ok && d.NewValueKnown("expected_groups") {
+			errs = append(errs, "`expected_groups` is required for conditions of type `outlier`")
+		}
+	} else {
+		for _, attr := range []string{"expected_groups", "ignore_overlap"} {
+			if d.HasChange(attr) {
+				errs = append(errs, fmt.Sprintf("`%s` can only be set for conditions of type `outlier`", attr))
+			}
+		}
+	}
+
+	// Summing the values of a query only makes sense when it aggregates
+	// them. Queries that do not parse are already reported by the schema.
+	if strings.EqualFold(d.Get("value_function").(string), "sum") && d.NewValueKnown("nrql.0.query") {
+		if q, err := nrql.Parse(d.Get("nrql.0.query").(string)); err == nil && len(q.Select) > 0 && !nrql.IsAggregation(q.Select[0].Expr) {
+			errs = append(errs, "`value_function` `sum` requires `nrql.query` to select an aggregation function, such as `count(*)`")
+		}
+	}
+
+	if d.NewValueKnown("term") {
+		errs = append(errs, validateNrqlConditionTerms(d.Get("term").(*schema.Set).List(), conditionType)...)
+	}
+
+	if d.NewValueKnown("fill_option") && d.NewValueKnown("fill_value") {
+		if !strings.EqualFold(d.Get("fill_option").(string), "static") && d.Get("fill_value").(float64) != 0 {
+			errs = append(errs, "`fill_value` can only be set when `fill_option` is `static`")
+		}
+	}
+
+	if _, ok := d.GetOk("expiration_duration"); !ok && d.NewValueKnown("expiration_duration") {
+		for _, attr := range []string{"open_violation_on_expiration", "close_violations_on_expiration"} {
+			if d.Get(attr).(bool) {
+				errs = append(errs, fmt.Sprintf("`%s` requires `expiration_duration` to be set", attr))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid NRQL alert condition:\n\n- %s", strings.Join(errs, "\n- "))
+	}
+
+	return nil
+}
+
+// validateNrqlConditionTerms returns what is wrong with the terms of a NRQL
+// alert condition of the given type.
+func validateNrqlConditionTerms(terms []interface{}, conditionType string) []string {
+	errs := []string{}
+	critical := 0
+
+	for _, t := range terms {
+		term := t.(map[string]interface{})
+
+		if term["priority"].(string) == "critical" {
+			critical++
+		}
+
+		if conditionType != "baseline" {
+			continue
+		}
+
+		if duration := term["duration"].(int); duration != 0 && (duration < 2 || duration > 60) {
+			errs = append(errs, fmt.Sprintf("`term.duration` must be between 2 and 60 for conditions of type `baseline`, got: %d", duration))
+		}
+
+		if duration := term["threshold_duration"].(int); duration != 0 && (duration < 120 || duration > 3600) {
+			errs = append(errs, fmt.Sprintf("`term.threshold_duration` must be between 120 and 3600 for conditions of type `baseline`, got: %d", duration))
+		}
+
+		if threshold := term["threshold"].(float64); threshold < 1 || threshold > 1000 {
+			errs = append(errs, fmt.Sprintf("`term.threshold` must be between 1 and 1000 for conditions of type `baseline`, got: %v", threshold))
+		}
+	}
+
+	if critical != 1 {
+		errs = append(errs, fmt.Sprintf("exactly one `term` must have a `priority` of `critical`, got: %d", critical))
+	}
+
+	return errs
+}
+
 func resourceNewRelicNrqlAlertConditionCreate(d *schema.ResourceData, meta interface{}) error {
+	started := time.Now()
+
 	providerConfig := meta.(*ProviderConfig)
-	client := providerConfig.NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	policyID := d.Get("policy_id").(int)
 	conditionType := d.Get("type").(string)
 
-	if canUseNerdGraphNrqlAlertConditions(providerConfig, conditionType) {
+	if canUseNerdGraphNrqlAlertConditions(providerConfig) {
 		accountID := selectAccountID(providerConfig, d)
 
-		conditionInput, err := expandNrqlAlertConditionInput(d)
+		conditionInput, err := expandNrqlConditionNerdGraphInput(d)
 		if err != nil {
 			return err
 		}
 
-		log.Printf("[INFO] Creating New Relic NRQL alert condition %s via NerdGraph API", conditionInput.Name)
-
-		id := strconv.Itoa(policyID)
+		log.Printf("[INFO] Creating New Relic NRQL alert condition %s via NerdGraph API", d.Get("name").(string))
 
-		var nrqlCondition *alerts.NrqlAlertCondition
-		if conditionType == "baseline" {
-			if nrqlCondition, err = client.Alerts.CreateNrqlConditionBaselineMutation(accountID, id, *conditionInput); err != nil {
-				return err
-			}
-		}
-
-		if conditionType == "static" {
-			if nrqlCondition, err = client.Alerts.CreateNrqlConditionStaticMutation(accountID, id, *conditionInput); err != nil {
-				return err
-			}
+		nrqlCondition, err := createNrqlCondition(client, accountID, conditionType, strconv.Itoa(policyID), conditionInput)
+		if err != nil {
+			return err
 		}
 
 		conditionID, err := strconv.Atoi(nrqlCondition.ID)
@@ -304,47 +481,54 @@ func resourceNewRelicNrqlAlertConditionCreate(d *schema.ResourceData, meta inter
 			return err
 		}
 
-		d.SetId(serializeIDs([]int{policyID, conditionID}))
+		d.SetId(nrqlConditionID.New(policyID, conditionID))
 
-		return resourceNewRelicNrqlAlertConditionRead(d, meta)
+		return resourceReadAfterCreate(resourceNewRelicNrqlAlertConditionRead, d, meta, started)
 	}
 
 	// Fallback to REST API
+	if err := checkNrqlConditionNerdGraphOnlyAttributes(d); err != nil {
+		return err
+	}
+
 	condition := expandNrqlAlertConditionStruct(d)
 
 	log.Printf("[INFO] Creating New Relic NRQL alert condition %s via REST API", condition.Name)
 
-	condition, err := client.Alerts.CreateNrqlCondition(policyID, *condition)
+	condition, err = client.Alerts.CreateNrqlCondition(policyID, *condition)
 	if err != nil {
 		return err
 	}
 
-	d.SetId(serializeIDs([]int{policyID, condition.ID}))
+	d.SetId(nrqlConditionID.New(policyID, condition.ID))
 
-	return resourceNewRelicNrqlAlertConditionRead(d, meta)
+	return resourceReadAfterCreate(resourceNewRelicNrqlAlertConditionRead, d, meta, started)
 }
 
 func resourceNewRelicNrqlAlertConditionRead(d *schema.ResourceData, meta interface{}) error {
 	providerConfig := meta.(*ProviderConfig)
-	client := providerConfig.NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Reading New Relic NRQL alert condition %s", d.Id())
 
-	ids, err := parseHashedIDs(d.Id())
+	ids, err := nrqlConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	policyID := ids[0]
-	conditionID := strconv.Itoa(ids[1])
-	conditionType := d.Get("type").(string)
+	policyID := ids.Int("policy_id")
+	conditionID := strconv.Itoa(ids.Int("condition_id"))
 
 	// NerdGraph
-	if canUseNerdGraphNrqlAlertConditions(providerConfig, conditionType) {
+	if canUseNerdGraphNrqlAlertConditions(providerConfig) {
 		accountID := selectAccountID(providerConfig, d)
 
-		var nrqlCondition *alerts.NrqlAlertCondition
-		nrqlCondition, err = client.Alerts.GetNrqlConditionQuery(accountID, conditionID)
+		var nrqlCondition *nerdGraphNrqlCondition
+		nrqlCondition, err = getNrqlCondition(client, accountID, conditionID)
 		if err != nil {
 			if _, ok := err.(*errors.NotFound); ok {
 				d.SetId("")
@@ -366,7 +550,7 @@ func resourceNewRelicNrqlAlertConditionRead(d *schema.ResourceData, meta interfa
 		return err
 	}
 
-	id := ids[1]
+	id := ids.Int("condition_id")
 
 	condition, err := client.Alerts.GetNrqlCondition(policyID, id)
 	if err != nil {
@@ -385,45 +569,41 @@ func resourceNewRelicNrqlAlertConditionRead(d *schema.ResourceData, meta interfa
 
 func resourceNewRelicNrqlAlertConditionUpdate(d *schema.ResourceData, meta interface{}) error {
 	providerConfig := meta.(*ProviderConfig)
-	client := providerConfig.NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	ids, err := parseHashedIDs(d.Id())
+	ids, err := nrqlConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	conditionID := ids[1]
+	conditionID := ids.Int("condition_id")
 	conditionType := d.Get("type").(string)
 
-	if canUseNerdGraphNrqlAlertConditions(providerConfig, conditionType) {
+	if canUseNerdGraphNrqlAlertConditions(providerConfig) {
 		accountID := selectAccountID(providerConfig, d)
 
-		var conditionInput *alerts.NrqlConditionInput
-		conditionInput, err = expandNrqlAlertConditionInput(d)
+		var conditionInput interface{}
+		conditionInput, err = expandNrqlConditionNerdGraphInput(d)
 		if err != nil {
 			return err
 		}
 
-		id := strconv.Itoa(conditionID)
-
-		if conditionType == "baseline" {
-			_, err = client.Alerts.UpdateNrqlConditionBaselineMutation(accountID, id, *conditionInput)
-			if err != nil {
-				return err
-			}
-		}
-
-		if conditionType == "static" {
-			_, err = client.Alerts.UpdateNrqlConditionStaticMutation(accountID, id, *conditionInput)
-			if err != nil {
-				return err
-			}
+		if _, err = updateNrqlCondition(client, accountID, conditionType, strconv.Itoa(conditionID), conditionInput); err != nil {
+			return err
 		}
 
 		return resourceNewRelicNrqlAlertConditionRead(d, meta)
 	}
 
 	// Fallback to REST API
+	if err = checkNrqlConditionNerdGraphOnlyAttributes(d); err != nil {
+		return err
+	}
+
 	condition := expandNrqlAlertConditionStruct(d)
 	condition.ID = conditionID
 
@@ -438,14 +618,18 @@ func resourceNewRelicNrqlAlertConditionUpdate(d *schema.ResourceData, meta inter
 }
 
 func resourceNewRelicNrqlAlertConditionDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	ids, err := parseHashedIDs(d.Id())
+	ids, err := nrqlConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	conditionID := ids[1]
+	conditionID := ids.Int("condition_id")
 
 	log.Printf("[INFO] Deleting New Relic NRQL alert condition %d", conditionID)
 
@@ -457,6 +641,67 @@ func resourceNewRelicNrqlAlertConditionDelete(d *schema.ResourceData, meta inter
 	return nil
 }
 
-func canUseNerdGraphNrqlAlertConditions(providerConfig *ProviderConfig, conditionType string) bool {
-	return providerConfig.hasNerdGraphCredentials() && conditionType != "outlier"
+func canUseNerdGraphNrqlAlertConditions(providerConfig *ProviderConfig) bool {
+	return providerConfig.hasNerdGraphCredentials()
+}
+
+// The attributes of NRQL alert conditions that the REST API does not have.
+var nrqlConditionNerdGraphOnlyAttributes = []string{
+	"aggregation_window",
+	"fill_option",
+	"fill_value",
+	"expiration_duration",
+	"open_violation_on_expiration",
+	"close_violations_on_expiration",
+}
+
+// checkNrqlConditionNerdGraphOnlyAttributes returns an error when attributes
+// that can only be managed through NerdGraph are set or changed. Values read
+// from NerdGraph into the state, which are kept when they are not configured,
+// are not an error.
+func checkNrqlConditionNerdGraphOnlyAttributes(d *schema.ResourceData) error {
+	for _, attr := range nrqlConditionNerdGraphOnlyAttributes {
+		if d.HasChange(attr) {
+			return fmt.Errorf("attribute `%s` requires the provider's personal_api_key to be set, as it can only be managed through NerdGraph", attr)
+		}
+	}
+
+	return nil
+}
+
+// Lists the IDs of the NRQL conditions of a policy with a name, along with
+// their type when they are found through NerdGraph.
+func listNrqlAlertConditionIDs(client *nr.NewRelic, providerConfig *ProviderConfig, policyID int, name string) ([]string, error) {
+	ids := []string{}
+
+	if providerConfig.hasNerdGraphCredentials() {
+		conditions, err := client.Alerts.SearchNrqlConditionsQuery(providerConfig.AccountID, alerts.NrqlConditionsSearchCriteria{
+			PolicyID: strconv.Itoa(policyID),
+			Name:     name,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range conditions {
+			if c.Name == name {
+				ids = append(ids, c.ID+compoundid.Separator+strings.ToLower(string(c.Type)))
+			}
+		}
+
+		return ids, nil
+	}
+
+	conditions, err := client.Alerts.ListNrqlConditions(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range conditions {
+		if c.Name == name {
+			ids = append(ids, strconv.Itoa(c.ID))
+		}
+	}
+
+	return ids, nil
 }
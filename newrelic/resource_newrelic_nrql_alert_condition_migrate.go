@@ -0,0 +1,128 @@
+package newrelic
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// The schema of NRQL alert conditions at version 0. Only the types of the
+// attributes are kept, as they are all the state needs to be decoded.
+func resourceNewRelicNrqlAlertConditionV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"policy_id":   {Type: schema.TypeInt, Required: true},
+			"name":        {Type: schema.TypeString, Required: true},
+			"runbook_url": {Type: schema.TypeString, Optional: true},
+			"enabled":     {Type: schema.TypeBool, Optional: true},
+			"type":        {Type: schema.TypeString, Optional: true},
+			"nrql": {
+				Type:     schema.TypeList,
+				Required: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"query":             {Type: schema.TypeString, Required: true},
+						"since_value":       {Type: schema.TypeString, Optional: true},
+						"evaluation_offset": {Type: schema.TypeInt, Optional: true},
+					},
+				},
+			},
+			"term": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"duration":              {Type: schema.TypeInt, Optional: true},
+						"operator":              {Type: schema.TypeString, Optional: true},
+						"priority":              {Type: schema.TypeString, Optional: true},
+						"threshold":             {Type: schema.TypeFloat, Required: true},
+						"time_function":         {Type: schema.TypeString, Optional: true},
+						"threshold_occurrences": {Type: schema.TypeString, Optional: true},
+						"threshold_duration":    {Type: schema.TypeInt, Optional: true},
+					},
+				},
+			},
+			"expected_groups":                {Type: schema.TypeInt, Optional: true},
+			"ignore_overlap":                 {Type: schema.TypeBool, Optional: true},
+			"violation_time_limit_seconds":   {Type: schema.TypeInt, Optional: true},
+			"value_function":                 {Type: schema.TypeString, Optional: true},
+			"account_id":                     {Type: schema.TypeInt, Optional: true},
+			"description":                    {Type: schema.TypeString, Optional: true},
+			"violation_time_limit":           {Type: schema.TypeString, Optional: true},
+			"aggregation_window":             {Type: schema.TypeInt, Optional: true, Computed: true},
+			"fill_option":                    {Type: schema.TypeString, Optional: true, Computed: true},
+			"fill_value":                     {Type: schema.TypeFloat, Optional: true},
+			"expiration_duration":            {Type: schema.TypeInt, Optional: true},
+			"open_violation_on_expiration":   {Type: schema.TypeBool, Optional: true},
+			"close_violations_on_expiration": {Type: schema.TypeBool, Optional: true},
+			"baseline_direction":             {Type: schema.TypeString, Optional: true},
+		},
+	}
+}
+
+// Rewrites the deprecated attributes of a NRQL alert condition at version 0
+// to the ones that replaced them in NerdGraph, so that they can be dropped
+// from the configuration without a diff:
+//
+//	nrql.since_value              => nrql.evaluation_offset
+//	term.duration (minutes)       => term.threshold_duration (seconds)
+//	term.time_function            => term.threshold_occurrences
+//	violation_time_limit_seconds  => violation_time_limit
+//
+// The state is rewritten whichever API the condition is managed through, as
+// both read conditions back into the attributes their configuration uses.
+func resourceNewRelicNrqlAlertConditionStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if nrql, ok := rawState["nrql"].([]interface{}); ok && len(nrql) > 0 {
+		if block, ok := nrql[0].(map[string]interface{}); ok {
+			sinceValue, _ := block["since_value"].(string)
+			if offset, err := strconv.Atoi(sinceValue); err == nil && stateInt(block["evaluation_offset"]) == 0 {
+				block["evaluation_offset"] = offset
+				delete(block, "since_value")
+			}
+		}
+	}
+
+	if terms, ok := rawState["term"].([]interface{}); ok {
+		for _, t := range terms {
+			term, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if duration := stateInt(term["duration"]); duration > 0 && stateInt(term["threshold_duration"]) == 0 {
+				term["threshold_duration"] = duration * 60
+				delete(term, "duration")
+			}
+
+			timeFunction, _ := term["time_function"].(string)
+			occurrences, _ := term["threshold_occurrences"].(string)
+			if o, ok := timeFunctionMap[timeFunction]; ok && occurrences == "" {
+				term["threshold_occurrences"] = string(o)
+				delete(term, "time_function")
+			}
+		}
+	}
+
+	seconds := stateInt(rawState["violation_time_limit_seconds"])
+	timeLimit, _ := rawState["violation_time_limit"].(string)
+	if l, ok := violationTimeLimitMap[seconds]; ok && timeLimit == "" {
+		rawState["violation_time_limit"] = string(l)
+		delete(rawState, "violation_time_limit_seconds")
+	}
+
+	return rawState, nil
+}
+
+// stateInt returns the value of a number in a raw state, which is decoded from
+// JSON as a float64.
+func stateInt(v interface{}) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	}
+
+	return 0
+}
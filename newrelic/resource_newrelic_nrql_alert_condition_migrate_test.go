@@ -0,0 +1,86 @@
+package newrelic
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func testNrqlAlertConditionStateV0() map[string]interface{} {
+	return map[string]interface{}{
+		"id":                           "123:456",
+		"policy_id":                    float64(123),
+		"name":                         "foo",
+		"type":                         "static",
+		"violation_time_limit_seconds": float64(14400),
+		"nrql": []interface{}{
+			map[string]interface{}{
+				"query":       "SELECT count(*) FROM Transaction",
+				"since_value": "3",
+			},
+		},
+		"term": []interface{}{
+			map[string]interface{}{
+				"duration":      float64(5),
+				"operator":      "above",
+				"priority":      "critical",
+				"threshold":     1.5,
+				"time_function": "any",
+			},
+		},
+	}
+}
+
+func TestResourceNewRelicNrqlAlertConditionStateUpgradeV0(t *testing.T) {
+	expected := map[string]interface{}{
+		"id":                   "123:456",
+		"policy_id":            float64(123),
+		"name":                 "foo",
+		"type":                 "static",
+		"violation_time_limit": "FOUR_HOURS",
+		"nrql": []interface{}{
+			map[string]interface{}{
+				"query":             "SELECT count(*) FROM Transaction",
+				"evaluation_offset": 3,
+			},
+		},
+		"term": []interface{}{
+			map[string]interface{}{
+				"threshold_duration":    300,
+				"operator":              "above",
+				"priority":              "critical",
+				"threshold":             1.5,
+				"threshold_occurrences": "AT_LEAST_ONCE",
+			},
+		},
+	}
+
+	meta := &ProviderConfig{AccountID: 123, PersonalAPIKey: "foo"}
+
+	actual, err := resourceNewRelicNrqlAlertConditionStateUpgradeV0(testNrqlAlertConditionStateV0(), meta)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	// The upgraded state is valid for the current schema.
+	_, err = schema.JSONMapToStateValue(actual, resourceNewRelicNrqlAlertCondition().CoreConfigSchema())
+	require.NoError(t, err)
+
+	// Upgrading twice changes nothing.
+	actual, err = resourceNewRelicNrqlAlertConditionStateUpgradeV0(actual, meta)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+// The upgrade only depends on the state, not on the credentials of the
+// provider, which may not even be configured yet.
+func TestResourceNewRelicNrqlAlertConditionStateUpgradeV0_Meta(t *testing.T) {
+	expected, err := resourceNewRelicNrqlAlertConditionStateUpgradeV0(testNrqlAlertConditionStateV0(), &ProviderConfig{PersonalAPIKey: "foo"})
+	require.NoError(t, err)
+
+	for _, meta := range []interface{}{nil, &ProviderConfig{}} {
+		actual, err := resourceNewRelicNrqlAlertConditionStateUpgradeV0(testNrqlAlertConditionStateV0(), meta)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+}
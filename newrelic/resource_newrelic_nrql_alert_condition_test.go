@@ -2,13 +2,17 @@ package newrelic
 
 import (
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccNewRelicNrqlAlertCondition_Basic(t *testing.T) {
@@ -44,6 +48,14 @@ func TestAccNewRelicNrqlAlertCondition_Basic(t *testing.T) {
 				ImportStateVerifyIgnore: []string{"term", "nrql", "violation_time_limit"},
 				ImportStateIdFunc:       testAccImportStateIDFunc(resourceName, "static"),
 			},
+			// Test: Import by name
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("policy:tf-test-%[1]s/condition:tf-test-%[1]s", rName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"term", "nrql", "violation_time_limit"},
+			},
 		},
 	})
 }
@@ -71,13 +83,211 @@ func TestAccNewRelicNrqlAlertCondition_TypeOutlier(t *testing.T) {
 				ImportStateVerify: true,
 				// Ignore items with deprecated fields because
 				// we don't set deprecated fields on import
-				ImportStateVerifyIgnore: []string{"account_id", "term", "nrql", "violation_time_limit"},
-				ImportStateIdFunc:       testAccImportStateIDFunc(resourceName, "outlier"),
+				ImportStateVerifyIgnore: []string{
+					"account_id",
+					"term",
+					"nrql",
+					"violation_time_limit",
+					"violation_time_limit_seconds",
+					"value_function", // does not exist for type `outlier`
+				},
+				ImportStateIdFunc: testAccImportStateIDFunc(resourceName, "outlier"),
 			},
 		},
 	})
 }
 
+func TestNewRelicNrqlAlertCondition_CustomizeDiff(t *testing.T) {
+	term := func(priority string, threshold float64, duration int) map[string]interface{} {
+		return map[string]interface{}{
+			"priority":              priority,
+			"operator":              "above",
+			"threshold":             threshold,
+			"threshold_duration":    duration,
+			"threshold_occurrences": "all",
+		}
+	}
+
+	cases := []struct {
+		query string
+		cfg   map[string]interface{}
+		errs  []string
+	}{
+		{
+			cfg: map[string]interface{}{
+				"type":               "baseline",
+				"baseline_direction": "upper_only",
+				"term":               []interface{}{term("critical", 5, 300), term("warning", 3, 600)},
+			},
+		},
+		{
+			cfg: map[string]interface{}{
+				"type":            "outlier",
+				"expected_groups": 2,
+				"ignore_overlap":  true,
+				"term":            []interface{}{term("critical", 0.5, 7200)},
+			},
+		},
+		{
+			cfg: map[string]interface{}{
+				"type":           "baseline",
+				"value_function": "sum",
+				"term":           []interface{}{term("critical", 0.5, 7200)},
+			},
+			errs: []string{
+				"`baseline_direction` is required for conditions of type `baseline`",
+				"`value_function` cannot be set for conditions of type `baseline`",
+				"`term.threshold_duration` must be between 120 and 3600 for conditions of type `baseline`, got: 7200",
+				"`term.threshold` must be between 1 and 1000 for conditions of type `baseline`, got: 0.5",
+			},
+		},
+		{
+			cfg: map[string]interface{}{
+				"type":            "static",
+				"expected_groups": 2,
+				"ignore_overlap":  true,
+				"term":            []interface{}{term("critical", 1, 120), term("critical", 2, 120)},
+			},
+			errs: []string{
+				"`expected_groups` can only be set for conditions of type `outlier`",
+				"`ignore_overlap` can only be set for conditions of type `outlier`",
+				"exactly one `term` must have a `priority` of `critical`, got: 2",
+			},
+		},
+		{
+			cfg: map[string]interface{}{
+				"type": "outlier",
+				"term": []interface{}{term("warning", 1, 120)},
+			},
+			errs: []string{
+				"`expected_groups` is required for conditions of type `outlier`",
+				"exactly one `term` must have a `priority` of `critical`, got: 0",
+			},
+		},
+		{
+			cfg: map[string]interface{}{
+				"fill_option":                    "last_value",
+				"fill_value":                     1.5,
+				"close_violations_on_expiration": true,
+				"term":                           []interface{}{term("critical", 1, 120)},
+			},
+			errs: []string{
+				"`fill_value` can only be set when `fill_option` is `static`",
+				"`close_violations_on_expiration` requires `expiration_duration` to be set",
+			},
+		},
+		{
+			cfg: map[string]interface{}{
+				"value_function": "sum",
+				"term":           []interface{}{term("critical", 1, 120)},
+			},
+		},
+		{
+			query: "SELECT latest(duration) - duration FROM Transaction",
+			cfg: map[string]interface{}{
+				"value_function": "SUM",
+				"term":           []interface{}{term("critical", 1, 120)},
+			},
+		},
+		{
+			query: "SELECT duration FROM Transaction WHERE appName = 'checkout'",
+			cfg: map[string]interface{}{
+				"value_function": "sum",
+				"term":           []interface{}{term("critical", 1, 120)},
+			},
+			errs: []string{
+				"`value_function` `sum` requires `nrql.query` to select an aggregation function, such as `count(*)`",
+			},
+		},
+	}
+
+	r := resourceNewRelicNrqlAlertCondition()
+
+	for _, tc := range cases {
+		tc.cfg["policy_id"] = 123
+		tc.cfg["name"] = "foo"
+		if tc.query == "" {
+			tc.query = "SELECT count(*) FROM Transaction"
+		}
+		tc.cfg["nrql"] = []interface{}{map[string]interface{}{"query": tc.query}}
+
+		_, err := r.Diff(nil, terraform.NewResourceConfigRaw(tc.cfg), nil)
+		if len(tc.errs) == 0 {
+			require.NoError(t, err)
+			continue
+		}
+
+		require.Error(t, err)
+		for _, e := range tc.errs {
+			require.Contains(t, err.Error(), "\n- "+e)
+		}
+		require.Equal(t, len(tc.errs), strings.Count(err.Error(), "\n- "))
+	}
+}
+
+func TestCheckNrqlConditionNerdGraphOnlyAttributes(t *testing.T) {
+	r := resourceNewRelicNrqlAlertCondition()
+
+	config := func(attrs map[string]interface{}) *terraform.ResourceConfig {
+		raw := map[string]interface{}{
+			"policy_id": 123,
+			"name":      "foo",
+			"nrql":      []interface{}{map[string]interface{}{"query": "SELECT count(*) FROM Transaction", "since_value": "3"}},
+			"term": []interface{}{map[string]interface{}{
+				"operator":      "above",
+				"priority":      "critical",
+				"threshold":     1.0,
+				"duration":      5,
+				"time_function": "all",
+			}},
+		}
+		for k, v := range attrs {
+			raw[k] = v
+		}
+
+		return terraform.NewResourceConfigRaw(raw)
+	}
+
+	check := func(state *terraform.InstanceState, c *terraform.ResourceConfig) error {
+		diff, err := r.Diff(state, c, nil)
+		require.NoError(t, err)
+
+		d, err := schema.InternalMap(r.Schema).Data(state, diff)
+		require.NoError(t, err)
+
+		return checkNrqlConditionNerdGraphOnlyAttributes(d)
+	}
+
+	// Attributes that are set when creating a condition.
+	require.NoError(t, check(nil, config(nil)))
+	require.EqualError(t, check(nil, config(map[string]interface{}{"fill_option": "static", "fill_value": 1.0})),
+		"attribute `fill_option` requires the provider's personal_api_key to be set, as it can only be managed through NerdGraph")
+
+	// Attributes read from NerdGraph into the state, which are only an
+	// error when they are changed.
+	state := &terraform.InstanceState{
+		ID: "123:456",
+		Attributes: map[string]string{
+			"id":                   "123:456",
+			"policy_id":            "123",
+			"name":                 "foo",
+			"type":                 "static",
+			"value_function":       "single_value",
+			"nrql.#":               "1",
+			"nrql.0.query":         "SELECT count(*) FROM Transaction",
+			"nrql.0.since_value":   "3",
+			"aggregation_window":   "60",
+			"fill_option":          "none",
+			"violation_time_limit": "ONE_HOUR",
+		},
+	}
+
+	require.NoError(t, check(state, config(map[string]interface{}{"name": "bar"})))
+	require.NoError(t, check(state, config(map[string]interface{}{"aggregation_window": 60})))
+	require.EqualError(t, check(state, config(map[string]interface{}{"aggregation_window": 120})),
+		"attribute `aggregation_window` requires the provider's personal_api_key to be set, as it can only be managed through NerdGraph")
+}
+
 func TestAccNewRelicNrqlAlertCondition_MissingPolicy(t *testing.T) {
 	rName := acctest.RandString(5)
 
@@ -98,6 +308,53 @@ func TestAccNewRelicNrqlAlertCondition_MissingPolicy(t *testing.T) {
 	})
 }
 
+// Outlier conditions created through the REST API, which defaults their since
+// value, can be managed through NerdGraph once a personal API key is set.
+func TestAccNewRelicNrqlAlertCondition_OutlierRESTToNerdGraph(t *testing.T) {
+	resourceName := "newrelic_nrql_alert_condition.foo"
+	rName := acctest.RandString(5)
+
+	if os.Getenv(resource.TestEnvVar) == "" {
+		t.Skipf("Acceptance tests skipped unless env '%s' set", resource.TestEnvVar)
+	}
+
+	// Policies can only be managed through NerdGraph, so the condition is
+	// added to one made beforehand.
+	testAccPreCheck(t)
+	policyID := testAccCreateNewRelicAlertPolicyREST(t, fmt.Sprintf("tf-test-%s", rName))
+	defer testAccDeleteNewRelicAlertPolicy(fmt.Sprintf("tf-test-%s", rName))()
+
+	// Not run in parallel, as the personal API key is unset for a step.
+	personalAPIKey := os.Getenv("NEWRELIC_PERSONAL_API_KEY")
+	defer os.Setenv("NEWRELIC_PERSONAL_API_KEY", personalAPIKey)
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckNewRelicNrqlAlertConditionDestroy,
+		Steps: []resource.TestStep{
+			// Test: Create through the REST API
+			{
+				PreConfig: func() { os.Unsetenv("NEWRELIC_PERSONAL_API_KEY") },
+				Config:    testAccNewRelicNrqlAlertConditionConfigOutlierWithoutOffset(rName, policyID, "https://www.example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNewRelicNrqlAlertConditionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "nrql.0.evaluation_offset", "3"),
+				),
+			},
+			// Test: Update through NerdGraph
+			{
+				PreConfig: func() { os.Setenv("NEWRELIC_PERSONAL_API_KEY", personalAPIKey) },
+				Config:    testAccNewRelicNrqlAlertConditionConfigOutlierWithoutOffset(rName, policyID, "https://www.example.com/runbook"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNewRelicNrqlAlertConditionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "runbook_url", "https://www.example.com/runbook"),
+					resource.TestCheckResourceAttr(resourceName, "nrql.0.evaluation_offset", "3"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccNewRelicNrqlAlertCondition_NerdGraphBaseline(t *testing.T) {
 	resourceName := "newrelic_nrql_alert_condition.foo"
 	rName := acctest.RandString(5)
@@ -261,6 +518,168 @@ func TestAccNewRelicNrqlAlertCondition_NerdGraphStatic(t *testing.T) {
 	})
 }
 
+func TestAccNewRelicNrqlAlertCondition_NerdGraphStaticSignal(t *testing.T) {
+	resourceName := "newrelic_nrql_alert_condition.foo"
+	rName := acctest.RandString(5)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckNewRelicNrqlAlertConditionDestroy,
+		Steps: []resource.TestStep{
+			// Test: Create (static)
+			{
+				Config: testAccNewRelicNrqlAlertConditionNerdGraphConfig(
+					rName,
+					"static",
+					3,
+					120,
+					`value_function = "single_value"
+	aggregation_window = 120
+	fill_option = "static"
+	fill_value = 1.5
+	expiration_duration = 600
+	open_violation_on_expiration = true`,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNewRelicNrqlAlertConditionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "aggregation_window", "120"),
+					resource.TestCheckResourceAttr(resourceName, "fill_option", "static"),
+					resource.TestCheckResourceAttr(resourceName, "fill_value", "1.5"),
+					resource.TestCheckResourceAttr(resourceName, "expiration_duration", "600"),
+					resource.TestCheckResourceAttr(resourceName, "open_violation_on_expiration", "true"),
+					resource.TestCheckResourceAttr(resourceName, "close_violations_on_expiration", "false"),
+				),
+			},
+			// Test: Update (static)
+			{
+				Config: testAccNewRelicNrqlAlertConditionNerdGraphConfig(
+					rName,
+					"static",
+					3,
+					120,
+					`value_function = "single_value"
+	fill_option = "LAST_VALUE"
+	expiration_duration = 1200
+	close_violations_on_expiration = true`,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNewRelicNrqlAlertConditionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "aggregation_window", "120"),
+					resource.TestCheckResourceAttr(resourceName, "fill_option", "last_value"),
+					resource.TestCheckResourceAttr(resourceName, "fill_value", "0"),
+					resource.TestCheckResourceAttr(resourceName, "expiration_duration", "1200"),
+					resource.TestCheckResourceAttr(resourceName, "open_violation_on_expiration", "false"),
+					resource.TestCheckResourceAttr(resourceName, "close_violations_on_expiration", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccNewRelicNrqlAlertCondition_NerdGraphBaselineSignal(t *testing.T) {
+	resourceName := "newrelic_nrql_alert_condition.foo"
+	rName := acctest.RandString(5)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckNewRelicNrqlAlertConditionDestroy,
+		Steps: []resource.TestStep{
+			// Test: Create (baseline)
+			{
+				Config: testAccNewRelicNrqlAlertConditionNerdGraphConfig(
+					rName,
+					"baseline",
+					3,
+					120,
+					`baseline_direction = "upper_only"
+	aggregation_window = 300
+	fill_option = "none"`,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNewRelicNrqlAlertConditionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "aggregation_window", "300"),
+					resource.TestCheckResourceAttr(resourceName, "fill_option", "none"),
+					resource.TestCheckResourceAttr(resourceName, "expiration_duration", "0"),
+					resource.TestCheckResourceAttr(resourceName, "close_violations_on_expiration", "false"),
+				),
+			},
+			// Test: Import
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"term",
+					"nrql",
+					"violation_time_limit",
+					"value_function", // does not exist for type `baseline`
+				},
+				ImportStateIdFunc: testAccImportStateIDFunc(resourceName, "baseline"),
+			},
+		},
+	})
+}
+
+func TestAccNewRelicNrqlAlertCondition_NerdGraphOutlier(t *testing.T) {
+	resourceName := "newrelic_nrql_alert_condition.foo"
+	rName := acctest.RandString(5)
+	conditionType := "outlier"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckNewRelicNrqlAlertConditionDestroy,
+		Steps: []resource.TestStep{
+			// Test: Create (NerdGraph)
+			{
+				Config: testAccNewRelicNrqlAlertConditionNerdGraphConfig(
+					rName,
+					conditionType,
+					5,
+					3600,
+					"expected_groups = 2",
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNewRelicNrqlAlertConditionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "description", "test description"),
+					resource.TestCheckResourceAttr(resourceName, "expected_groups", "2"),
+					resource.TestCheckResourceAttr(resourceName, "ignore_overlap", "false"),
+				),
+			},
+			// Test: Update (NerdGraph)
+			{
+				Config: testAccNewRelicNrqlAlertConditionNerdGraphConfig(
+					rName,
+					conditionType,
+					20,
+					1800,
+					"expected_groups = 3\n\tignore_overlap = true",
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNewRelicNrqlAlertConditionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "expected_groups", "3"),
+					resource.TestCheckResourceAttr(resourceName, "ignore_overlap", "true"),
+				),
+			},
+			// Test: Import
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"term",
+					"nrql",
+					"violation_time_limit",
+					"value_function", // does not exist for type `outlier`
+				},
+				ImportStateIdFunc: testAccImportStateIDFunc(resourceName, "outlier"),
+			},
+		},
+	})
+}
+
 func testAccCheckNewRelicNrqlAlertConditionDestroy(s *terraform.State) error {
 	providerConfig := testAccProvider.Meta().(*ProviderConfig)
 	client := providerConfig.NewClient
@@ -274,13 +693,13 @@ func testAccCheckNewRelicNrqlAlertConditionDestroy(s *terraform.State) error {
 		var accountID int
 		var err error
 
-		ids, err := parseHashedIDs(r.Primary.ID)
+		ids, err := nrqlConditionID.Parse(r.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := ids[0]
-		conditionID := strconv.Itoa(ids[1])
+		policyID := ids.Int("policy_id")
+		conditionID := strconv.Itoa(ids.Int("condition_id"))
 
 		if hasNerdGraphCreds {
 			accountID = providerConfig.AccountID
@@ -296,7 +715,7 @@ func testAccCheckNewRelicNrqlAlertConditionDestroy(s *terraform.State) error {
 				return fmt.Errorf("NRQL Alert condition still exists") //nolint:golint
 			}
 		} else {
-			id := ids[1]
+			id := ids.Int("condition_id")
 			if _, err = client.Alerts.GetNrqlCondition(policyID, id); err == nil {
 				return fmt.Errorf("NRQL Alert condition still exists") //nolint:golint
 			}
@@ -323,15 +742,15 @@ func testAccCheckNewRelicNrqlAlertConditionExists(n string) resource.TestCheckFu
 		var accountID int
 		var err error
 
-		ids, err := parseHashedIDs(rs.Primary.ID)
+		ids, err := nrqlConditionID.Parse(rs.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := ids[0]
-		conditionID := strconv.Itoa(ids[1])
+		policyID := ids.Int("policy_id")
+		conditionID := strconv.Itoa(ids.Int("condition_id"))
 
-		if hasNerdGraphCreds && rs.Primary.Attributes["type"] != "outlier" {
+		if hasNerdGraphCreds {
 			accountID = providerConfig.AccountID
 
 			if rs.Primary.Attributes["account_id"] != "" {
@@ -354,12 +773,12 @@ func testAccCheckNewRelicNrqlAlertConditionExists(n string) resource.TestCheckFu
 			return nil
 		}
 
-		found, err := client.Alerts.GetNrqlCondition(policyID, ids[1])
+		found, err := client.Alerts.GetNrqlCondition(policyID, ids.Int("condition_id"))
 		if err != nil {
 			return err
 		}
 
-		if found.ID != ids[1] {
+		if found.ID != ids.Int("condition_id") {
 			return fmt.Errorf("alert condition not found: %v - %v", conditionID, found)
 		}
 
@@ -458,6 +877,30 @@ resource "newrelic_nrql_alert_condition" "foo" {
 `, name)
 }
 
+func testAccNewRelicNrqlAlertConditionConfigOutlierWithoutOffset(name string, policyID int, runbookURL string) string {
+	return fmt.Sprintf(`
+resource "newrelic_nrql_alert_condition" "foo" {
+	type            = "outlier"
+	name            = "tf-test-outlier-%[1]s"
+	policy_id       = %[2]d
+	runbook_url     = "%[3]s"
+	expected_groups = 2
+
+	nrql {
+		query = "SELECT percentile(duration, 95) FROM Transaction WHERE appName = 'ExampleAppName' FACET host"
+	}
+
+	term {
+		operator      = "above"
+		priority      = "critical"
+		threshold     = 0.065
+		duration      = 5
+		time_function = "all"
+	}
+}
+`, name, policyID, runbookURL)
+}
+
 // Uses deprecated attributes for test case
 func testAccNewRelicNrqlAlertConditionNerdGraphConfigDeprecated(
 	name string,
@@ -2,9 +2,11 @@ package newrelic
 
 import (
 	"log"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
 )
 
@@ -15,7 +17,12 @@ func resourceNewRelicPluginsAlertCondition() *schema.Resource {
 		Update: resourceNewRelicPluginsAlertConditionUpdate,
 		Delete: resourceNewRelicPluginsAlertConditionDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByName(policyConditionID, policyConditionNames, importAlertConditionByName("plugins alert condition", listPluginsAlertConditionIDs)),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"policy_id": {
@@ -120,34 +127,43 @@ func resourceNewRelicPluginsAlertCondition() *schema.Resource {
 }
 
 func resourceNewRelicPluginsAlertConditionCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	condition := expandPluginsCondition(d)
 	policyID := d.Get("policy_id").(int)
 
 	log.Printf("[INFO] Creating New Relic alert condition %s", condition.Name)
 
-	condition, err := client.Alerts.CreatePluginsCondition(policyID, *condition)
+	condition, err = client.Alerts.CreatePluginsCondition(policyID, *condition)
 	if err != nil {
 		return err
 	}
 
-	d.SetId(serializeIDs([]int{policyID, condition.ID}))
+	d.SetId(policyConditionID.New(policyID, condition.ID))
 
 	return nil
 }
 
 func resourceNewRelicPluginsAlertConditionRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Reading New Relic alert condition %s", d.Id())
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	policyID := ids[0]
-	id := ids[1]
+	policyID := ids.Int("policy_id")
+	id := ids.Int("condition_id")
 
 	_, err = client.Alerts.GetPolicy(policyID)
 	if err != nil {
@@ -174,15 +190,20 @@ func resourceNewRelicPluginsAlertConditionRead(d *schema.ResourceData, meta inte
 }
 
 func resourceNewRelicPluginsAlertConditionUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	condition := expandPluginsCondition(d)
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	id := ids[1]
+	id := ids.Int("condition_id")
 	condition.ID = id
 
 	log.Printf("[INFO] Updating New Relic alert condition %d", id)
@@ -196,14 +217,18 @@ func resourceNewRelicPluginsAlertConditionUpdate(d *schema.ResourceData, meta in
 }
 
 func resourceNewRelicPluginsAlertConditionDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	id := ids[1]
+	id := ids.Int("condition_id")
 
 	log.Printf("[INFO] Deleting New Relic alert condition %d", id)
 
@@ -215,3 +240,19 @@ func resourceNewRelicPluginsAlertConditionDelete(d *schema.ResourceData, meta in
 
 	return nil
 }
+
+func listPluginsAlertConditionIDs(client *nr.NewRelic, providerConfig *ProviderConfig, policyID int, name string) ([]string, error) {
+	conditions, err := client.Alerts.ListPluginsConditions(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, c := range conditions {
+		if c.Name == name {
+			ids = append(ids, strconv.Itoa(c.ID))
+		}
+	}
+
+	return ids, nil
+}
@@ -133,13 +133,13 @@ func testAccCheckNewRelicPluginsAlertConditionDestroy(s *terraform.State) error
 		if r.Type != "newrelic_plugins_alert_condition" {
 			continue
 		}
-		ids, err := parseIDs(r.Primary.ID, 2)
+		ids, err := policyConditionID.Parse(r.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := ids[0]
-		id := ids[1]
+		policyID := ids.Int("policy_id")
+		id := ids.Int("condition_id")
 
 		_, err = client.Alerts.GetPluginsCondition(policyID, id)
 		if err == nil {
@@ -162,13 +162,13 @@ func testAccCheckNewRelicPluginsAlertConditionExists(n string) resource.TestChec
 
 		client := testAccProvider.Meta().(*ProviderConfig).NewClient
 
-		ids, err := parseIDs(rs.Primary.ID, 2)
+		ids, err := policyConditionID.Parse(rs.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := ids[0]
-		id := ids[1]
+		policyID := ids.Int("policy_id")
+		id := ids.Int("condition_id")
 
 		found, err := client.Alerts.GetPluginsCondition(policyID, id)
 		if err != nil {
@@ -2,8 +2,11 @@ package newrelic
 
 import (
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
 )
@@ -15,7 +18,12 @@ func resourceNewRelicSyntheticsAlertCondition() *schema.Resource {
 		Update: resourceNewRelicSyntheticsAlertConditionUpdate,
 		Delete: resourceNewRelicSyntheticsAlertConditionDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByName(policyConditionID, policyConditionNames, importAlertConditionByName("Synthetics alert condition", listSyntheticsAlertConditionIDs)),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"policy_id": {
@@ -65,12 +73,12 @@ func expandSyntheticsCondition(d *schema.ResourceData) *alerts.SyntheticsConditi
 }
 
 func flattenSyntheticsCondition(condition *alerts.SyntheticsCondition, d *schema.ResourceData) error {
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	policyID := ids[0]
+	policyID := ids.Int("policy_id")
 
 	d.Set("policy_id", policyID)
 	d.Set("monitor_id", condition.MonitorID)
@@ -82,35 +90,45 @@ func flattenSyntheticsCondition(condition *alerts.SyntheticsCondition, d *schema
 }
 
 func resourceNewRelicSyntheticsAlertConditionCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	started := time.Now()
+
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	policyID := d.Get("policy_id").(int)
 	condition := expandSyntheticsCondition(d)
 
 	log.Printf("[INFO] Creating New Relic Synthetics alert condition %s", condition.Name)
 
-	condition, err := client.Alerts.CreateSyntheticsCondition(policyID, *condition)
+	condition, err = client.Alerts.CreateSyntheticsCondition(policyID, *condition)
 	if err != nil {
 		return err
 	}
 
-	d.SetId(serializeIDs([]int{policyID, condition.ID}))
+	d.SetId(policyConditionID.New(policyID, condition.ID))
 
-	return resourceNewRelicSyntheticsAlertConditionRead(d, meta)
+	return resourceReadAfterCreate(resourceNewRelicSyntheticsAlertConditionRead, d, meta, started)
 }
 
 func resourceNewRelicSyntheticsAlertConditionRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Reading New Relic Synthetics alert condition %s", d.Id())
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	policyID := ids[0]
-	id := ids[1]
+	policyID := ids.Int("policy_id")
+	id := ids.Int("condition_id")
 
 	_, err = client.Alerts.GetPolicy(policyID)
 	if err != nil {
@@ -135,15 +153,20 @@ func resourceNewRelicSyntheticsAlertConditionRead(d *schema.ResourceData, meta i
 }
 
 func resourceNewRelicSyntheticsAlertConditionUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	condition := expandSyntheticsCondition(d)
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	id := ids[1]
+	id := ids.Int("condition_id")
 
 	condition.ID = id
 
@@ -158,14 +181,18 @@ func resourceNewRelicSyntheticsAlertConditionUpdate(d *schema.ResourceData, meta
 }
 
 func resourceNewRelicSyntheticsAlertConditionDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	id := ids[1]
+	id := ids.Int("condition_id")
 
 	log.Printf("[INFO] Deleting New Relic Synthetics alert condition %d", id)
 
@@ -177,3 +204,19 @@ func resourceNewRelicSyntheticsAlertConditionDelete(d *schema.ResourceData, meta
 
 	return nil
 }
+
+func listSyntheticsAlertConditionIDs(client *nr.NewRelic, providerConfig *ProviderConfig, policyID int, name string) ([]string, error) {
+	conditions, err := client.Alerts.ListSyntheticsConditions(policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, c := range conditions {
+		if c.Name == name {
+			ids = append(ids, strconv.Itoa(c.ID))
+		}
+	}
+
+	return ids, nil
+}
@@ -75,13 +75,13 @@ func testAccCheckNewRelicSyntheticsAlertConditionDestroy(s *terraform.State) err
 			continue
 		}
 
-		ids, err := parseIDs(r.Primary.ID, 2)
+		ids, err := policyConditionID.Parse(r.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := ids[0]
-		id := ids[1]
+		policyID := ids.Int("policy_id")
+		id := ids.Int("condition_id")
 
 		_, err = client.Alerts.GetSyntheticsCondition(policyID, id)
 		if err == nil {
@@ -104,13 +104,13 @@ func testAccCheckNewRelicSyntheticsAlertConditionExists(n string) resource.TestC
 
 		client := testAccProvider.Meta().(*ProviderConfig).NewClient
 
-		ids, err := parseIDs(rs.Primary.ID, 2)
+		ids, err := policyConditionID.Parse(rs.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		policyID := ids[0]
-		id := ids[1]
+		policyID := ids.Int("policy_id")
+		id := ids.Int("condition_id")
 
 		found, err := client.Alerts.GetSyntheticsCondition(policyID, id)
 		if err != nil {
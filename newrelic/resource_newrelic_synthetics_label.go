@@ -7,15 +7,35 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
 	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+
+	"github.com/terraform-providers/terraform-provider-newrelic/internal/compoundid"
 )
 
+// The ID of a label of a Synthetics monitor.
+var syntheticsLabelID = &compoundid.Schema{
+	Formats: []compoundid.Format{
+		{
+			Version: 1,
+			Parts: []compoundid.Part{
+				{Name: "monitor_id", Kind: compoundid.String},
+				{Name: "type", Kind: compoundid.String},
+				{Name: "value", Kind: compoundid.String},
+			},
+		},
+	},
+}
+
 func resourceNewRelicSyntheticsLabel() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceNewRelicSyntheticsLabelCreate,
 		Read:   resourceNewRelicSyntheticsLabelRead,
 		Delete: resourceNewRelicSyntheticsLabelDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateWithCompoundID(syntheticsLabelID),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"monitor_id": {
@@ -54,33 +74,46 @@ func resourceNewRelicSyntheticsLabel() *schema.Resource {
 }
 
 func resourceNewRelicSyntheticsLabelCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	monitorID := d.Get("monitor_id").(string)
 	label := expandSyntheticsLabel(d)
 
 	log.Printf("[INFO] Creating New Relic Synthetics label %s:%s", label.Type, label.Value)
 
-	err := client.Synthetics.AddMonitorLabel(monitorID, label.Type, label.Value)
+	err = client.Synthetics.AddMonitorLabel(monitorID, label.Type, label.Value)
 	if err != nil {
 		return err
 	}
 
-	d.SetId(strings.Join([]string{monitorID, label.Type, label.Value}, ":"))
+	d.SetId(syntheticsLabelID.New(monitorID, label.Type, label.Value))
 
 	return nil
 }
 
 func resourceNewRelicSyntheticsLabelRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Reading New Relic Synthetics label %s", d.Id())
 
-	ids := strings.Split(d.Id(), ":")
-	monitorID := ids[0]
-	labelType := ids[1]
-	value := ids[2]
+	ids, err := syntheticsLabelID.Parse(d.Id())
+	if err != nil {
+		return err
+	}
+
+	monitorID := ids.Value("monitor_id")
+	labelType := ids.Value("type")
+	value := ids.Value("value")
 
-	_, err := client.Synthetics.GetMonitor(monitorID)
+	_, err = client.Synthetics.GetMonitor(monitorID)
 	if err != nil {
 		if _, ok := err.(*errors.NotFound); ok {
 			d.SetId("")
@@ -113,16 +146,24 @@ func resourceNewRelicSyntheticsLabelRead(d *schema.ResourceData, meta interface{
 }
 
 func resourceNewRelicSyntheticsLabelDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	ids, err := syntheticsLabelID.Parse(d.Id())
+	if err != nil {
+		return err
+	}
 
-	ids := strings.Split(d.Id(), ":")
-	monitorID := ids[0]
-	labelType := ids[1]
-	value := ids[2]
+	monitorID := ids.Value("monitor_id")
+	labelType := ids.Value("type")
+	value := ids.Value("value")
 
 	log.Printf("[INFO] Deleting New Relic alert condition %s", d.Id())
 
-	err := client.Synthetics.DeleteMonitorLabel(monitorID, labelType, value)
+	err = client.Synthetics.DeleteMonitorLabel(monitorID, labelType, value)
 	if err != nil {
 		return err
 	}
@@ -141,8 +182,12 @@ func expandSyntheticsLabel(d *schema.ResourceData) *synthetics.MonitorLabel {
 }
 
 func flattenSyntheticsLabel(label *synthetics.MonitorLabel, d *schema.ResourceData) error {
-	ids := strings.Split(d.Id(), ":")
-	monitorID := ids[0]
+	ids, err := syntheticsLabelID.Parse(d.Id())
+	if err != nil {
+		return err
+	}
+
+	monitorID := ids.Value("monitor_id")
 
 	d.Set("monitor_id", monitorID)
 	d.Set("type", label.Type)
@@ -3,13 +3,28 @@ package newrelic
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
 	"github.com/newrelic/newrelic-client-go/pkg/synthetics"
+
+	"github.com/terraform-providers/terraform-provider-newrelic/internal/compoundid"
 )
 
+var syntheticsMonitorID = &compoundid.Schema{
+	Formats: []compoundid.Format{
+		{
+			Version: 1,
+			Parts: []compoundid.Part{
+				{Name: "monitor_id", Kind: compoundid.String},
+			},
+		},
+	},
+}
+
 func resourceNewRelicSyntheticsMonitor() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceNewRelicSyntheticsMonitorCreate,
@@ -17,7 +32,12 @@ func resourceNewRelicSyntheticsMonitor() *schema.Resource {
 		Update: resourceNewRelicSyntheticsMonitorUpdate,
 		Delete: resourceNewRelicSyntheticsMonitorDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByName(syntheticsMonitorID, []string{"name"}, importSyntheticsMonitorByName),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"type": {
@@ -93,6 +113,9 @@ func resourceNewRelicSyntheticsMonitor() *schema.Resource {
 				Optional:    true,
 				Description: "Fail the monitor check if redirected.",
 			},
+			"deletion_protection": deletionProtectionSchema(),
+			"tags":                entityTagsSchema(),
+			"tags_all":            entityTagsAllSchema(),
 		},
 	}
 }
@@ -193,7 +216,14 @@ func readSyntheticsMonitorStruct(monitor *synthetics.Monitor, d *schema.Resource
 }
 
 func resourceNewRelicSyntheticsMonitorCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	started := time.Now()
+
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	monitorStruct := buildSyntheticsMonitorStruct(d)
 
 	log.Printf("[INFO] Creating New Relic Synthetics monitor %s", monitorStruct.Name)
@@ -204,11 +234,15 @@ func resourceNewRelicSyntheticsMonitorCreate(d *schema.ResourceData, meta interf
 	}
 
 	d.SetId(monitor.ID)
-	return resourceNewRelicSyntheticsMonitorRead(d, meta)
+	return resourceReadAfterCreate(resourceNewRelicSyntheticsMonitorRead, d, meta, started)
 }
 
 func resourceNewRelicSyntheticsMonitorRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Reading New Relic Synthetics monitor %s", d.Id())
 
@@ -226,10 +260,15 @@ func resourceNewRelicSyntheticsMonitorRead(d *schema.ResourceData, meta interfac
 }
 
 func resourceNewRelicSyntheticsMonitorUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	log.Printf("[INFO] Updating New Relic Synthetics monitor %s", d.Id())
 
-	_, err := client.Synthetics.UpdateMonitor(*buildSyntheticsUpdateMonitorArgs(d))
+	_, err = client.Synthetics.UpdateMonitor(*buildSyntheticsUpdateMonitorArgs(d))
 	if err != nil {
 		return err
 	}
@@ -238,7 +277,11 @@ func resourceNewRelicSyntheticsMonitorUpdate(d *schema.ResourceData, meta interf
 }
 
 func resourceNewRelicSyntheticsMonitorDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Deleting New Relic Synthetics monitor %s", d.Id())
 
@@ -248,3 +291,19 @@ func resourceNewRelicSyntheticsMonitorDelete(d *schema.ResourceData, meta interf
 
 	return nil
 }
+
+func importSyntheticsMonitorByName(client *nr.NewRelic, providerConfig *ProviderConfig, d *schema.ResourceData, names []string) (string, error) {
+	monitors, err := providerConfig.listSyntheticsMonitors(client)
+	if err != nil {
+		return "", err
+	}
+
+	ids := []string{}
+	for _, m := range monitors {
+		if m.Name == names[0] {
+			ids = append(ids, m.ID)
+		}
+	}
+
+	return importMatch("Synthetics monitor", names[0], ids)
+}
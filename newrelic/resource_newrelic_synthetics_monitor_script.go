@@ -2,6 +2,7 @@ package newrelic
 
 import (
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
@@ -17,6 +18,11 @@ func resourceNewRelicSyntheticsMonitorScript() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: importSyntheticsMonitorScript,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 		Schema: map[string]*schema.Schema{
 			"monitor_id": {
 				Type:        schema.TypeString,
@@ -47,22 +53,32 @@ func buildSyntheticsMonitorScriptStruct(d *schema.ResourceData) *synthetics.Moni
 }
 
 func resourceNewRelicSyntheticsMonitorScriptCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	started := time.Now()
+
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	id := d.Get("monitor_id").(string)
 	log.Printf("[INFO] Creating New Relic Synthetics monitor script %s", id)
 
-	_, err := client.Synthetics.UpdateMonitorScript(id, *buildSyntheticsMonitorScriptStruct(d))
+	_, err = client.Synthetics.UpdateMonitorScript(id, *buildSyntheticsMonitorScriptStruct(d))
 	if err != nil {
 		return err
 	}
 
 	d.SetId(id)
-	return resourceNewRelicSyntheticsMonitorScriptRead(d, meta)
+	return resourceReadAfterCreate(resourceNewRelicSyntheticsMonitorScriptRead, d, meta, started)
 }
 
 func resourceNewRelicSyntheticsMonitorScriptRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Reading New Relic Synthetics script %s", d.Id())
 
@@ -81,11 +97,15 @@ func resourceNewRelicSyntheticsMonitorScriptRead(d *schema.ResourceData, meta in
 }
 
 func resourceNewRelicSyntheticsMonitorScriptUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Creating New Relic Synthetics monitor script %s", d.Id())
 
-	_, err := client.Synthetics.UpdateMonitorScript(d.Id(), *buildSyntheticsMonitorScriptStruct(d))
+	_, err = client.Synthetics.UpdateMonitorScript(d.Id(), *buildSyntheticsMonitorScriptStruct(d))
 	if err != nil {
 		return err
 	}
@@ -95,7 +115,11 @@ func resourceNewRelicSyntheticsMonitorScriptUpdate(d *schema.ResourceData, meta
 }
 
 func resourceNewRelicSyntheticsMonitorScriptDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Deleting New Relic Synthetics monitor script %s", d.Id())
 
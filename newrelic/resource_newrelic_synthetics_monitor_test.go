@@ -1,7 +1,9 @@
 package newrelic
 
 import (
+	"encoding/base64"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
@@ -38,6 +40,13 @@ func TestAccNewRelicSyntheticsMonitor_Basic(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			// Test: Import by name
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("name:%s-updated", rName),
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -174,6 +183,82 @@ func TestAccNewRelicSyntheticsMonitor_ScriptAPI(t *testing.T) {
 	})
 }
 
+func TestAccNewRelicSyntheticsMonitor_DefaultTags(t *testing.T) {
+	resourceName := "newrelic_synthetics_monitor.foo"
+	rName := acctest.RandString(5)
+
+	// Not run in parallel, as the default tags apply to every resource
+	// configured by the provider.
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckNewRelicSyntheticsMonitorDestroy,
+		Steps: []resource.TestStep{
+			// Test: Create
+			{
+				Config: testAccNewRelicSyntheticsMonitorConfigDefaultTags(rName, `team = "checkout"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNewRelicSyntheticsMonitorExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "tags_all.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, "tags_all.team", "checkout"),
+					resource.TestCheckResourceAttr(resourceName, "tags_all.managed-by", "terraform"),
+					testAccCheckNewRelicSyntheticsMonitorTags(resourceName, map[string]string{"team": "checkout", "managed-by": "terraform"}),
+				),
+			},
+			// Test: Update
+			{
+				Config: testAccNewRelicSyntheticsMonitorConfigDefaultTags(rName, `env = "production"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckNewRelicSyntheticsMonitorExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "tags_all.%", "3"),
+					resource.TestCheckResourceAttr(resourceName, "tags_all.team", "platform"),
+					testAccCheckNewRelicSyntheticsMonitorTags(resourceName, map[string]string{"team": "platform", "managed-by": "terraform", "env": "production"}),
+				),
+			},
+			// Test: Import
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"tags"},
+			},
+		},
+	})
+}
+
+func testAccCheckNewRelicSyntheticsMonitorTags(n string, expected map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*ProviderConfig).NewClient
+
+		guid := base64.RawStdEncoding.EncodeToString([]byte(fmt.Sprintf("%d|SYNTH|MONITOR|%s", testAccountID, rs.Primary.ID)))
+
+		tags, err := client.Entities.ListTags(guid)
+		if err != nil {
+			return err
+		}
+
+		found := map[string]string{}
+		for _, tag := range tags {
+			found[tag.Key] = strings.Join(tag.Values, ",")
+		}
+
+		for k, v := range expected {
+			if found[k] != v {
+				return fmt.Errorf("expected tag %s to be %q, got %q", k, v, found[k])
+			}
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckNewRelicSyntheticsMonitorExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -361,3 +446,27 @@ resource "newrelic_synthetics_monitor" "foo" {
 }
 `, name)
 }
+
+func testAccNewRelicSyntheticsMonitorConfigDefaultTags(name string, tags string) string {
+	return fmt.Sprintf(`
+provider "newrelic" {
+	default_tags = {
+		team         = "platform"
+		"managed-by" = "terraform"
+	}
+}
+
+resource "newrelic_synthetics_monitor" "foo" {
+	name      = "%[1]s-default-tags"
+	type      = "SIMPLE"
+	frequency = 1
+	status    = "DISABLED"
+	locations = ["AWS_US_EAST_1"]
+	uri       = "https://example.com"
+
+	tags = {
+		%[2]s
+	}
+}
+`, name, tags)
+}
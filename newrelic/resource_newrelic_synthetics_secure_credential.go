@@ -3,6 +3,7 @@ package newrelic
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
@@ -17,6 +18,11 @@ func resourceNewRelicSyntheticsSecureCredential() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
+		},
 		Schema: map[string]*schema.Schema{
 			"key": {
 				Type:        schema.TypeString,
@@ -56,22 +62,33 @@ func resourceNewRelicSyntheticsSecureCredential() *schema.Resource {
 }
 
 func resourceNewRelicSyntheticsSecureCredentialCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	started := time.Now()
+
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	sc := expandSyntheticsSecureCredential(d)
 
 	log.Printf("[INFO] Creating New Relic Synthetics secure credential %s", sc.Key)
 
-	sc, err := client.Synthetics.AddSecureCredential(sc.Key, sc.Value, sc.Description)
+	sc, err = client.Synthetics.AddSecureCredential(sc.Key, sc.Value, sc.Description)
 	if err != nil {
 		return err
 	}
 
 	d.SetId(sc.Key)
-	return resourceNewRelicSyntheticsSecureCredentialRead(d, meta)
+	return resourceReadAfterCreate(resourceNewRelicSyntheticsSecureCredentialRead, d, meta, started)
 }
 
 func resourceNewRelicSyntheticsSecureCredentialRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Reading New Relic Synthetics secure credential %s", d.Id())
 
@@ -89,12 +106,17 @@ func resourceNewRelicSyntheticsSecureCredentialRead(d *schema.ResourceData, meta
 }
 
 func resourceNewRelicSyntheticsSecureCredentialUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	log.Printf("[INFO] Updating New Relic Synthetics secure credential %s", d.Id())
 
 	sc := expandSyntheticsSecureCredential(d)
 
-	_, err := client.Synthetics.UpdateSecureCredential(sc.Key, sc.Value, sc.Description)
+	_, err = client.Synthetics.UpdateSecureCredential(sc.Key, sc.Value, sc.Description)
 	if err != nil {
 		return err
 	}
@@ -103,7 +125,11 @@ func resourceNewRelicSyntheticsSecureCredentialUpdate(d *schema.ResourceData, me
 }
 
 func resourceNewRelicSyntheticsSecureCredentialDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Deleting New Relic Synthetics secure credential %s", d.Id())
 
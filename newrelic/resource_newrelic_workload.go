@@ -1,15 +1,31 @@
 package newrelic
 
 import (
-	"fmt"
 	"log"
-	"strconv"
-	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	nr "github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/errors"
+
+	"github.com/terraform-providers/terraform-provider-newrelic/internal/compoundid"
 )
 
+// The ID of a workload, which holds the account it belongs to and both of
+// its IDs, as NerdGraph uses each of them in different places.
+var workloadID = &compoundid.Schema{
+	Formats: []compoundid.Format{
+		{
+			Version: 1,
+			Parts: []compoundid.Part{
+				{Name: "account_id", Kind: compoundid.Int},
+				{Name: "workload_id", Kind: compoundid.Int},
+				{Name: "guid", Kind: compoundid.String},
+			},
+		},
+	},
+}
+
 func resourceNewRelicWorkload() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceNewRelicWorkloadCreate,
@@ -17,7 +33,12 @@ func resourceNewRelicWorkload() *schema.Resource {
 		Update: resourceNewRelicWorkloadUpdate,
 		Delete: resourceNewRelicWorkloadDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceImportStateByName(workloadID, []string{"name"}, importWorkloadByName),
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultResourceTimeout),
+			Update: schema.DefaultTimeout(defaultResourceTimeout),
+			Delete: schema.DefaultTimeout(defaultResourceTimeout),
 		},
 		Schema: map[string]*schema.Schema{
 			"account_id": {
@@ -45,9 +66,10 @@ func resourceNewRelicWorkload() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"query": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "The query.",
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "The query.",
+							ValidateFunc: validateEntitySearchQuery,
 						},
 					},
 				},
@@ -79,12 +101,22 @@ func resourceNewRelicWorkload() *schema.Resource {
 				Computed:    true,
 				Description: "The URL of the workload.",
 			},
+			"deletion_protection": deletionProtectionSchema(),
+			"tags":                entityTagsSchema(),
+			"tags_all":            entityTagsAllSchema(),
 		},
 	}
 }
 
 func resourceNewRelicWorkloadCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	started := time.Now()
+
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	createInput := expandWorkloadCreateInput(d)
 	accountID := d.Get("account_id").(int)
 
@@ -95,25 +127,23 @@ func resourceNewRelicWorkloadCreate(d *schema.ResourceData, meta interface{}) er
 		return err
 	}
 
-	ids := workloadIDs{
-		AccountID: accountID,
-		ID:        created.ID,
-		GUID:      created.GUID,
-	}
-
-	d.SetId(ids.String())
-	return resourceNewRelicWorkloadRead(d, meta)
+	d.SetId(workloadID.New(accountID, created.ID, created.GUID))
+	return resourceReadAfterCreate(resourceNewRelicWorkloadRead, d, meta, started)
 }
 
 func resourceNewRelicWorkloadRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	ids, err := parseWorkloadIDs(d.Id())
+	ids, err := workloadID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	workload, err := client.Workloads.GetWorkload(ids.AccountID, ids.GUID)
+	workload, err := client.Workloads.GetWorkload(ids.Int("account_id"), ids.Value("guid"))
 	if err != nil {
 		if _, ok := err.(*errors.NotFound); ok {
 			d.SetId("")
@@ -127,17 +157,22 @@ func resourceNewRelicWorkloadRead(d *schema.ResourceData, meta interface{}) erro
 }
 
 func resourceNewRelicWorkloadUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
 	updateInput := expandWorkloadUpdateInput(d)
 
 	log.Printf("[INFO] Updating New Relic One workload %s", d.Id())
 
-	ids, err := parseWorkloadIDs(d.Id())
+	ids, err := workloadID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	_, err = client.Workloads.UpdateWorkload(ids.GUID, updateInput)
+	_, err = client.Workloads.UpdateWorkload(ids.Value("guid"), updateInput)
 	if err != nil {
 		return err
 	}
@@ -148,48 +183,43 @@ func resourceNewRelicWorkloadUpdate(d *schema.ResourceData, meta interface{}) er
 }
 
 func resourceNewRelicWorkloadDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*ProviderConfig).NewClient
+	client, cancel, err := resourceClient(d, meta, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
 	log.Printf("[INFO] Deleting New Relic One workload %s", d.Id())
 
-	ids, err := parseWorkloadIDs(d.Id())
+	ids, err := workloadID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	if _, err := client.Workloads.DeleteWorkload(ids.GUID); err != nil {
+	if _, err := client.Workloads.DeleteWorkload(ids.Value("guid")); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func parseWorkloadIDs(ids string) (*workloadIDs, error) {
-	split := strings.Split(ids, ":")
+func importWorkloadByName(client *nr.NewRelic, providerConfig *ProviderConfig, d *schema.ResourceData, names []string) (string, error) {
+	accountID := selectAccountID(providerConfig, d)
 
-	accountID, err := strconv.ParseInt(split[0], 10, 32)
+	workloads, err := client.Workloads.ListWorkloads(accountID)
 	if err != nil {
-		return nil, err
+		// Accounts without any workloads are reported as not found.
+		if _, ok := err.(*errors.NotFound); !ok {
+			return "", err
+		}
 	}
 
-	workloadID, err := strconv.ParseInt(split[1], 10, 32)
-	if err != nil {
-		return nil, err
+	ids := []string{}
+	for _, w := range workloads {
+		if w.Name == names[0] {
+			ids = append(ids, workloadID.New(accountID, w.ID, w.GUID))
+		}
 	}
 
-	return &workloadIDs{
-		AccountID: int(accountID),
-		ID:        int(workloadID),
-		GUID:      split[2],
-	}, nil
-}
-
-type workloadIDs struct {
-	AccountID int
-	ID        int
-	GUID      string
-}
-
-func (w *workloadIDs) String() string {
-	return fmt.Sprintf("%d:%d:%s", w.AccountID, w.ID, w.GUID)
+	return importMatch("workload", names[0], ids)
 }
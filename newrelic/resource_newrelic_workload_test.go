@@ -44,6 +44,14 @@ func TestAccNewRelicWorkload_Basic(t *testing.T) {
 				ImportStateVerify:       true,
 				ImportStateVerifyIgnore: []string{"entity_search_query", "composite_entity_search_query"},
 			},
+			// Test: Import by name
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("name:%s-updated", rName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"entity_search_query", "composite_entity_search_query"},
+			},
 		},
 	})
 }
@@ -116,19 +124,19 @@ func testAccCheckNewRelicWorkloadExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("no workload ID is set")
 		}
 
-		ids, err := parseWorkloadIDs(rs.Primary.ID)
+		ids, err := workloadID.Parse(rs.Primary.ID)
 		if err != nil {
 			return err
 		}
 
 		client := testAccProvider.Meta().(*ProviderConfig).NewClient
 
-		found, err := client.Workloads.GetWorkload(ids.AccountID, ids.GUID)
+		found, err := client.Workloads.GetWorkload(ids.Int("account_id"), ids.Value("guid"))
 		if err != nil {
 			return err
 		}
 
-		if found.GUID != ids.GUID {
+		if found.GUID != ids.Value("guid") {
 			return fmt.Errorf("workload not found: %v - %v", rs.Primary.ID, found)
 		}
 
@@ -143,12 +151,12 @@ func testAccCheckNewRelicWorkloadDestroy(s *terraform.State) error {
 			continue
 		}
 
-		ids, err := parseWorkloadIDs(r.Primary.ID)
+		ids, err := workloadID.Parse(r.Primary.ID)
 		if err != nil {
 			return err
 		}
 
-		_, err = client.Workloads.GetWorkload(ids.AccountID, ids.GUID)
+		_, err = client.Workloads.GetWorkload(ids.Int("account_id"), ids.Value("guid"))
 		if err == nil {
 			return fmt.Errorf("workload still exists")
 		}
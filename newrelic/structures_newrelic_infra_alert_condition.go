@@ -73,12 +73,12 @@ func expandInfraAlertThreshold(v interface{}) *alerts.InfrastructureConditionThr
 }
 
 func flattenInfraAlertCondition(condition *alerts.InfrastructureCondition, d *schema.ResourceData) error {
-	ids, err := parseIDs(d.Id(), 2)
+	ids, err := policyConditionID.Parse(d.Id())
 	if err != nil {
 		return err
 	}
 
-	policyID := ids[0]
+	policyID := ids.Int("policy_id")
 
 	d.Set("policy_id", policyID)
 	d.Set("name", condition.Name)
@@ -36,10 +36,10 @@ var (
 	violationTimeLimitMapNewOld = map[alerts.NrqlConditionViolationTimeLimit]int{
 		alerts.NrqlConditionViolationTimeLimits.OneHour:         3600,
 		alerts.NrqlConditionViolationTimeLimits.TwoHours:        7200,
-		alerts.NrqlConditionViolationTimeLimits.FourHours:       1440,
-		alerts.NrqlConditionViolationTimeLimits.EightHours:      2880,
-		alerts.NrqlConditionViolationTimeLimits.TwelveHours:     4320,
-		alerts.NrqlConditionViolationTimeLimits.TwentyFourHours: 8640,
+		alerts.NrqlConditionViolationTimeLimits.FourHours:       14400,
+		alerts.NrqlConditionViolationTimeLimits.EightHours:      28800,
+		alerts.NrqlConditionViolationTimeLimits.TwelveHours:     43200,
+		alerts.NrqlConditionViolationTimeLimits.TwentyFourHours: 86400,
 	}
 )
 
@@ -91,7 +91,7 @@ func expandNrqlAlertConditionInput(d *schema.ResourceData) (*alerts.NrqlConditio
 
 	input.Nrql = *nrql
 
-	terms, err := expandNrqlTerms(d.Get("term").(*schema.Set).List(), conditionType)
+	terms, err := expandNrqlTerms(d.Get("term").(*schema.Set).List())
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +101,84 @@ func expandNrqlAlertConditionInput(d *schema.ResourceData) (*alerts.NrqlConditio
 	return &input, nil
 }
 
+// NerdGraph
+func expandNrqlConditionNerdGraphInput(d *schema.ResourceData) (interface{}, error) {
+	if d.Get("type").(string) == "outlier" {
+		return expandNrqlConditionOutlierInput(d)
+	}
+
+	conditionInput, err := expandNrqlAlertConditionInput(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nrqlConditionInput{
+		NrqlConditionInput:          *conditionInput,
+		nrqlConditionSignalSettings: *expandNrqlConditionSignalSettings(d),
+	}, nil
+}
+
+// NerdGraph
+func expandNrqlConditionOutlierInput(d *schema.ResourceData) (*nrqlConditionOutlierInput, error) {
+	conditionInput, err := expandNrqlAlertConditionInput(d)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedGroups, ok := d.GetOk("expected_groups")
+	if !ok {
+		return nil, fmt.Errorf("attribute `%s` is required for nrql alert conditions of type `%+v`", "expected_groups", "outlier")
+	}
+
+	return &nrqlConditionOutlierInput{
+		NrqlConditionBase:           conditionInput.NrqlConditionBase,
+		nrqlConditionSignalSettings: *expandNrqlConditionSignalSettings(d),
+		ExpectedGroups:              expectedGroups.(int),
+		OpenViolationOnGroupOverlap: !d.Get("ignore_overlap").(bool),
+	}, nil
+}
+
+// NerdGraph
+func expandNrqlConditionSignalSettings(d *schema.ResourceData) *nrqlConditionSignalSettings {
+	signal := nrqlConditionSignal{}
+
+	if aggregationWindow, ok := d.GetOk("aggregation_window"); ok {
+		window := aggregationWindow.(int)
+		signal.AggregationWindow = &window
+	}
+
+	if fillOption, ok := d.GetOk("fill_option"); ok {
+		option := strings.ToUpper(fillOption.(string))
+		signal.FillOption = &option
+
+		// A fill value of 0 cannot be told apart from none being set, so it
+		// is sent whenever gaps are filled with a static value.
+		if option == "STATIC" {
+			fillValue := d.Get("fill_value").(float64)
+			signal.FillValue = &fillValue
+		}
+	}
+
+	expiration := nrqlConditionExpiration{
+		CloseViolationsOnExpiration: d.Get("close_violations_on_expiration").(bool),
+		OpenViolationOnExpiration:   d.Get("open_violation_on_expiration").(bool),
+	}
+
+	if expirationDuration, ok := d.GetOk("expiration_duration"); ok {
+		duration := expirationDuration.(int)
+		expiration.ExpirationDuration = &duration
+	}
+
+	return &nrqlConditionSignalSettings{
+		Signal:     &signal,
+		Expiration: &expiration,
+	}
+}
+
+// The evaluation offset of conditions that set neither `since_value` nor
+// `evaluation_offset`, which is the `since_value` the REST API defaults to.
+const nrqlConditionDefaultEvaluationOffset = 3
+
 // NerdGraph
 func expandNrql(d *schema.ResourceData, condition alerts.NrqlConditionInput) (*alerts.NrqlConditionQuery, error) {
 	var nrql alerts.NrqlConditionQuery
@@ -119,14 +197,14 @@ func expandNrql(d *schema.ResourceData, condition alerts.NrqlConditionInput) (*a
 	} else if evalOffset, ok := d.GetOk("nrql.0.evaluation_offset"); ok {
 		nrql.EvaluationOffset = evalOffset.(int)
 	} else {
-		return nil, fmt.Errorf("one of `since_value` or `evaluation_offset` must be configured for block `nrql`")
+		nrql.EvaluationOffset = nrqlConditionDefaultEvaluationOffset
 	}
 
 	return &nrql, nil
 }
 
 // NerdGraph
-func expandNrqlTerms(terms []interface{}, conditionType string) ([]alerts.NrqlConditionTerms, error) {
+func expandNrqlTerms(terms []interface{}) ([]alerts.NrqlConditionTerms, error) {
 	expanded := make([]alerts.NrqlConditionTerms, len(terms))
 
 	for i, t := range terms {
@@ -151,16 +229,6 @@ func expandNrqlTerms(terms []interface{}, conditionType string) ([]alerts.NrqlCo
 
 		threshold := term["threshold"].(float64)
 
-		if conditionType == "baseline" {
-			if duration < 120 || duration > 3600 {
-				return nil, fmt.Errorf("for baseline conditions duration must be in range %v, got %v", "[2, 60]", duration)
-			}
-
-			if threshold < 1 || threshold > 1000 {
-				return nil, fmt.Errorf("for baseline conditions threshold must be in range %v, got %v", "[1, 1000]", threshold)
-			}
-		}
-
 		timeFunctionIn := term["time_function"].(string)
 		thresholdOccurrencesIn := term["threshold_occurrences"].(string)
 
@@ -192,7 +260,7 @@ func expandNrqlTerms(terms []interface{}, conditionType string) ([]alerts.NrqlCo
 }
 
 // NerdGraph
-func flattenNrqlAlertCondition(accountID int, condition *alerts.NrqlAlertCondition, d *schema.ResourceData) error {
+func flattenNrqlAlertCondition(accountID int, condition *nerdGraphNrqlCondition, d *schema.ResourceData) error {
 	policyID, err := strconv.Atoi(condition.PolicyID)
 	if err != nil {
 		return err
@@ -232,9 +300,44 @@ func flattenNrqlAlertCondition(accountID int, condition *alerts.NrqlAlertConditi
 		d.Set("violation_time_limit", condition.ViolationTimeLimit)
 	}
 
-	// TODO: Handle `outlier` condition type fields when added to NerdGraph
-	// d.Set("expected_groups", condition.ExpectedGroups)
-	// d.Set("ignore_overlap", condition.IgnoreOverlap)
+	if signal := condition.Signal; signal != nil {
+		if signal.AggregationWindow != nil {
+			d.Set("aggregation_window", *signal.AggregationWindow)
+		}
+
+		if signal.FillOption != nil {
+			d.Set("fill_option", strings.ToLower(*signal.FillOption))
+		}
+
+		if signal.FillValue != nil {
+			d.Set("fill_value", *signal.FillValue)
+		} else {
+			d.Set("fill_value", nil)
+		}
+	}
+
+	if expiration := condition.Expiration; expiration != nil {
+		if expiration.ExpirationDuration != nil {
+			d.Set("expiration_duration", *expiration.ExpirationDuration)
+		} else {
+			d.Set("expiration_duration", nil)
+		}
+
+		d.Set("close_violations_on_expiration", expiration.CloseViolationsOnExpiration)
+		d.Set("open_violation_on_expiration", expiration.OpenViolationOnExpiration)
+	}
+
+	if conditionType == "outlier" {
+		if condition.ExpectedGroups != nil {
+			d.Set("expected_groups", *condition.ExpectedGroups)
+		}
+
+		// NerdGraph opens violations on overlapping groups unless told
+		// otherwise, which is the opposite of `ignore_overlap`.
+		if condition.OpenViolationOnGroupOverlap != nil {
+			d.Set("ignore_overlap", !*condition.OpenViolationOnGroupOverlap)
+		}
+	}
 
 	return nil
 }
@@ -358,6 +461,8 @@ func expandNrqlAlertConditionStruct(d *schema.ResourceData) *alerts.NrqlConditio
 
 	if sinceValue, ok := d.GetOk("nrql.0.since_value"); ok {
 		condition.Nrql.SinceValue = sinceValue.(string)
+	} else if evalOffset, ok := d.GetOk("nrql.0.evaluation_offset"); ok {
+		condition.Nrql.SinceValue = strconv.Itoa(evalOffset.(int))
 	}
 
 	if attr, ok := d.GetOk("runbook_url"); ok {
@@ -370,6 +475,8 @@ func expandNrqlAlertConditionStruct(d *schema.ResourceData) *alerts.NrqlConditio
 
 	if attr, ok := d.GetOkExists("violation_time_limit_seconds"); ok {
 		condition.ViolationCloseTimer = attr.(int)
+	} else if attr, ok := d.GetOk("violation_time_limit"); ok {
+		condition.ViolationCloseTimer = violationTimeLimitMapNewOld[alerts.NrqlConditionViolationTimeLimit(strings.ToUpper(attr.(string)))]
 	}
 
 	if attr, ok := d.GetOk("expected_groups"); ok {
@@ -386,12 +493,26 @@ func expandNrqlConditionTerms(terms []interface{}) []alerts.ConditionTerm {
 	for i, t := range terms {
 		term := t.(map[string]interface{})
 
+		// The attributes that replaced the deprecated ones in NerdGraph are
+		// converted back, so that either can be used.
+		duration := term["duration"].(int)
+		if duration == 0 {
+			thresholdDuration, _ := term["threshold_duration"].(int)
+			duration = thresholdDuration / 60
+		}
+
+		timeFunction := term["time_function"].(string)
+		if timeFunction == "" {
+			thresholdOccurrences, _ := term["threshold_occurrences"].(string)
+			timeFunction = timeFunctionMapNewOld[alerts.ThresholdOccurrence(strings.ToUpper(thresholdOccurrences))]
+		}
+
 		expanded[i] = alerts.ConditionTerm{
-			Duration:     term["duration"].(int),
+			Duration:     duration,
 			Operator:     alerts.OperatorType(term["operator"].(string)),
 			Priority:     alerts.PriorityType(term["priority"].(string)),
 			Threshold:    term["threshold"].(float64),
-			TimeFunction: alerts.TimeFunctionType(term["time_function"].(string)),
+			TimeFunction: alerts.TimeFunctionType(timeFunction),
 		}
 	}
 
@@ -399,31 +520,43 @@ func expandNrqlConditionTerms(terms []interface{}) []alerts.ConditionTerm {
 }
 
 // Deprecated
-func flattenNrqlQuery(nrql alerts.NrqlQuery) []interface{} {
+func flattenNrqlQuery(nrql alerts.NrqlQuery, configNrql map[string]interface{}) []interface{} {
 	m := map[string]interface{}{
-		"query":       nrql.Query,
-		"since_value": nrql.SinceValue,
+		"query": nrql.Query,
+	}
+
+	// The since value is kept in the attribute the configuration uses, so
+	// that it can be managed through either API.
+	svRaw := configNrql["since_value"]
+	offset, err := strconv.Atoi(nrql.SinceValue)
+
+	if (svRaw != nil && svRaw.(string) != "") || err != nil {
+		m["since_value"] = nrql.SinceValue
+	} else {
+		m["evaluation_offset"] = offset
 	}
 
 	return []interface{}{m}
 }
 
 // Deprecated
-func flattenNrqlConditionTerms(terms []alerts.ConditionTerm) []map[string]interface{} {
-	var t []map[string]interface{}
-
-	for _, src := range terms {
-		dst := map[string]interface{}{
-			"duration":      src.Duration,
-			"operator":      src.Operator,
-			"priority":      src.Priority,
-			"threshold":     src.Threshold,
-			"time_function": src.TimeFunction,
+//
+// The terms are flattened as they are from NerdGraph, so that the deprecated
+// attributes are only kept when the configuration uses them.
+func flattenNrqlConditionTerms(terms []alerts.ConditionTerm, configTerms []interface{}) []map[string]interface{} {
+	converted := make([]alerts.NrqlConditionTerms, len(terms))
+
+	for i, src := range terms {
+		converted[i] = alerts.NrqlConditionTerms{
+			Operator:             alerts.NrqlConditionOperator(src.Operator),
+			Priority:             alerts.NrqlConditionPriority(src.Priority),
+			Threshold:            src.Threshold,
+			ThresholdDuration:    src.Duration * 60,
+			ThresholdOccurrences: timeFunctionMap[string(src.TimeFunction)],
 		}
-		t = append(t, dst)
 	}
 
-	return t
+	return flattenNrqlTerms(converted, configTerms)
 }
 
 // Deprecated
@@ -432,7 +565,6 @@ func flattenNrqlConditionStruct(condition *alerts.NrqlCondition, d *schema.Resou
 	d.Set("runbook_url", condition.RunbookURL)
 	d.Set("enabled", condition.Enabled)
 	d.Set("type", strings.ToLower(condition.Type))
-	d.Set("violation_time_limit_seconds", condition.ViolationCloseTimer)
 	d.Set("expected_groups", condition.ExpectedGroups)
 	d.Set("ignore_overlap", condition.IgnoreOverlap)
 
@@ -442,11 +574,19 @@ func flattenNrqlConditionStruct(condition *alerts.NrqlCondition, d *schema.Resou
 		d.Set("value_function", condition.ValueFunction)
 	}
 
-	if err := d.Set("nrql", flattenNrqlQuery(condition.Nrql)); err != nil {
+	configuredNrql, _ := d.Get("nrql.0").(map[string]interface{})
+	if err := d.Set("nrql", flattenNrqlQuery(condition.Nrql, configuredNrql)); err != nil {
 		return err
 	}
 
-	terms := flattenNrqlConditionTerms(condition.Terms)
+	timeLimit, ok := violationTimeLimitMap[condition.ViolationCloseTimer]
+	if _, configured := d.GetOk("violation_time_limit_seconds"); configured || !ok {
+		d.Set("violation_time_limit_seconds", condition.ViolationCloseTimer)
+	} else {
+		d.Set("violation_time_limit", timeLimit)
+	}
+
+	terms := flattenNrqlConditionTerms(condition.Terms, d.Get("term").(*schema.Set).List())
 
 	if err := d.Set("term", terms); err != nil {
 		return fmt.Errorf("[DEBUG] Error setting alert condition terms: %#v", err)
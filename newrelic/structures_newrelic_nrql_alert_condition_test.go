@@ -3,6 +3,7 @@ package newrelic
 import (
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 	"github.com/stretchr/testify/require"
 )
@@ -32,6 +33,20 @@ func TestExpandNrqlConditionTerms(t *testing.T) {
 
 	require.NotNil(t, expanded)
 	require.Equal(t, expected, expanded)
+	// The attributes that replaced the deprecated ones are converted back.
+	flattened = []interface{}{
+		map[string]interface{}{
+			"duration":              0,
+			"operator":              "above",
+			"priority":              "critical",
+			"threshold":             1.5,
+			"time_function":         "",
+			"threshold_duration":    300,
+			"threshold_occurrences": "ALL",
+		},
+	}
+
+	require.Equal(t, expected, expandNrqlConditionTerms(flattened))
 }
 
 func TestFlattenNrql(t *testing.T) {
@@ -45,10 +60,42 @@ func TestFlattenNrql(t *testing.T) {
 		"since_value": "3",
 	}}
 
-	flattened := flattenNrqlQuery(expanded)
+	flattened := flattenNrqlQuery(expanded, map[string]interface{}{"since_value": "3"})
 
 	require.NotNil(t, flattened)
 	require.Equal(t, expected, flattened)
+
+	// Configurations without a since value get the one of the API as an
+	// evaluation offset, as they do from NerdGraph.
+	expected = []interface{}{map[string]interface{}{
+		"query":             "SELECT percentile(duration, 99) FROM Transaction FACET remote_ip",
+		"evaluation_offset": 3,
+	}}
+
+	require.Equal(t, expected, flattenNrqlQuery(expanded, nil))
+}
+
+func TestExpandNrql(t *testing.T) {
+	cases := []struct {
+		nrql map[string]interface{}
+		want int
+	}{
+		{nrql: map[string]interface{}{"since_value": "5"}, want: 5},
+		{nrql: map[string]interface{}{"evaluation_offset": 7}, want: 7},
+		// Configurations written for the REST API may set neither.
+		{nrql: map[string]interface{}{}, want: nrqlConditionDefaultEvaluationOffset},
+	}
+
+	for _, tc := range cases {
+		tc.nrql["query"] = "SELECT count(*) FROM Transaction"
+		d := schema.TestResourceDataRaw(t, resourceNewRelicNrqlAlertCondition().Schema, map[string]interface{}{
+			"nrql": []interface{}{tc.nrql},
+		})
+
+		nrql, err := expandNrql(d, alerts.NrqlConditionInput{})
+		require.NoError(t, err)
+		require.Equal(t, tc.want, nrql.EvaluationOffset)
+	}
 }
 
 func TestFlattenNrqlConditionTerms(t *testing.T) {
@@ -62,18 +109,167 @@ func TestFlattenNrqlConditionTerms(t *testing.T) {
 		},
 	}
 
+	configured := []interface{}{
+		map[string]interface{}{
+			"duration":      5,
+			"operator":      "above",
+			"priority":      "critical",
+			"threshold":     1.5,
+			"time_function": "all",
+		},
+	}
+
 	expected := []map[string]interface{}{
 		{
 			"duration":      5,
-			"operator":      alerts.OperatorTypes.Above,
-			"priority":      alerts.PriorityTypes.Critical,
+			"operator":      "above",
+			"priority":      "critical",
 			"threshold":     1.5,
-			"time_function": alerts.TimeFunctionTypes.All,
+			"time_function": "all",
 		},
 	}
 
-	flattened := flattenNrqlConditionTerms(expanded)
+	flattened := flattenNrqlConditionTerms(expanded, configured)
 
 	require.NotNil(t, flattened)
 	require.Equal(t, expected, flattened)
+
+	// The attributes that replaced the deprecated ones are used otherwise.
+	expected = []map[string]interface{}{
+		{
+			"threshold_duration":    300,
+			"operator":              "above",
+			"priority":              "critical",
+			"threshold":             1.5,
+			"threshold_occurrences": alerts.ThresholdOccurrences.All,
+		},
+	}
+
+	require.Equal(t, expected, flattenNrqlConditionTerms(expanded, nil))
+}
+
+// Conditions read through the REST API keep the deprecated attributes only
+// when they are configured, so that upgraded states need no changes.
+func TestFlattenNrqlConditionStruct(t *testing.T) {
+	condition := &alerts.NrqlCondition{
+		Name:                "foo",
+		Type:                "static",
+		ViolationCloseTimer: 14400,
+		Nrql:                alerts.NrqlQuery{Query: "SELECT count(*) FROM Transaction", SinceValue: "3"},
+		Terms: []alerts.ConditionTerm{
+			{Duration: 5, Operator: "above", Priority: "critical", Threshold: 1.5, TimeFunction: alerts.TimeFunctionTypes.Any},
+		},
+	}
+
+	deprecated := map[string]interface{}{
+		"violation_time_limit_seconds": 14400,
+		"nrql":                         []interface{}{map[string]interface{}{"query": "SELECT count(*) FROM Transaction", "since_value": "3"}},
+		"term":                         []interface{}{map[string]interface{}{"duration": 5, "threshold": 1.5, "time_function": "any"}},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceNewRelicNrqlAlertCondition().Schema, deprecated)
+	require.NoError(t, flattenNrqlConditionStruct(condition, d))
+	require.Equal(t, 14400, d.Get("violation_time_limit_seconds"))
+	require.Equal(t, "", d.Get("violation_time_limit"))
+	require.Equal(t, "3", d.Get("nrql.0.since_value"))
+	require.Equal(t, 5, d.Get("term").(*schema.Set).List()[0].(map[string]interface{})["duration"])
+
+	d = schema.TestResourceDataRaw(t, resourceNewRelicNrqlAlertCondition().Schema, map[string]interface{}{})
+	require.NoError(t, flattenNrqlConditionStruct(condition, d))
+	require.Equal(t, 0, d.Get("violation_time_limit_seconds"))
+	require.Equal(t, "FOUR_HOURS", d.Get("violation_time_limit"))
+	require.Equal(t, 3, d.Get("nrql.0.evaluation_offset"))
+
+	term := d.Get("term").(*schema.Set).List()[0].(map[string]interface{})
+	require.Equal(t, 300, term["threshold_duration"])
+	require.Equal(t, "AT_LEAST_ONCE", term["threshold_occurrences"])
+}
+
+func TestExpandNrqlConditionOutlierInput(t *testing.T) {
+	raw := map[string]interface{}{
+		"policy_id":            123,
+		"name":                 "outlier",
+		"type":                 "outlier",
+		"violation_time_limit": "one_hour",
+		"ignore_overlap":       true,
+		"nrql": []interface{}{map[string]interface{}{
+			"query":             "SELECT count(*) FROM Transaction FACET appName",
+			"evaluation_offset": 3,
+		}},
+		"term": []interface{}{map[string]interface{}{
+			"operator":              "above",
+			"priority":              "critical",
+			"threshold":             1.5,
+			"threshold_duration":    120,
+			"threshold_occurrences": "all",
+		}},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceNewRelicNrqlAlertCondition().Schema, raw)
+
+	_, err := expandNrqlConditionOutlierInput(d)
+	require.EqualError(t, err, "attribute `expected_groups` is required for nrql alert conditions of type `outlier`")
+
+	raw["expected_groups"] = 2
+	d = schema.TestResourceDataRaw(t, resourceNewRelicNrqlAlertCondition().Schema, raw)
+
+	input, err := expandNrqlConditionOutlierInput(d)
+	require.NoError(t, err)
+	require.Equal(t, 2, input.ExpectedGroups)
+	require.False(t, input.OpenViolationOnGroupOverlap)
+	require.Equal(t, alerts.NrqlConditionViolationTimeLimits.OneHour, input.ViolationTimeLimit)
+}
+
+func TestExpandNrqlConditionSignalSettings(t *testing.T) {
+	aggregationWindow, fillOption, fillValue, expirationDuration := 120, "STATIC", 0.0, 600
+
+	cases := []struct {
+		raw  map[string]interface{}
+		want nrqlConditionSignalSettings
+	}{
+		{
+			raw: map[string]interface{}{},
+			want: nrqlConditionSignalSettings{
+				Signal:     &nrqlConditionSignal{},
+				Expiration: &nrqlConditionExpiration{},
+			},
+		},
+		{
+			raw: map[string]interface{}{
+				"aggregation_window":           120,
+				"fill_option":                  "static",
+				"expiration_duration":          600,
+				"open_violation_on_expiration": true,
+			},
+			want: nrqlConditionSignalSettings{
+				Signal: &nrqlConditionSignal{
+					AggregationWindow: &aggregationWindow,
+					FillOption:        &fillOption,
+					FillValue:         &fillValue,
+				},
+				Expiration: &nrqlConditionExpiration{
+					ExpirationDuration:        &expirationDuration,
+					OpenViolationOnExpiration: true,
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		d := schema.TestResourceDataRaw(t, resourceNewRelicNrqlAlertCondition().Schema, tc.raw)
+
+		require.Equal(t, tc.want, *expandNrqlConditionSignalSettings(d))
+	}
+}
+
+func TestNrqlConditionMapsAreInverse(t *testing.T) {
+	require.Equal(t, len(timeFunctionMap), len(timeFunctionMapNewOld))
+	for old, new := range timeFunctionMap {
+		require.Equal(t, old, timeFunctionMapNewOld[new])
+	}
+
+	require.Equal(t, len(violationTimeLimitMap), len(violationTimeLimitMapNewOld))
+	for old, new := range violationTimeLimitMap {
+		require.Equal(t, old, violationTimeLimitMapNewOld[new])
+	}
 }
@@ -1,9 +1,13 @@
 package newrelic
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+
+	"github.com/terraform-providers/terraform-provider-newrelic/internal/nrql"
 )
 
 func float64Gte(gte float64) schema.SchemaValidateFunc {
@@ -60,3 +64,115 @@ func float64AtLeast(min float64) schema.SchemaValidateFunc {
 		return
 	}
 }
+
+// validateNrql is a SchemaValidateFunc which tests if the provided value is a
+// NRQL query with a valid syntax. Unknown keywords are only warnings, as the
+// parser may not know all the syntax New Relic accepts, but queries which
+// could not be valid, such as those missing FROM, are errors.
+func validateNrql(i interface{}, k string) (s []string, es []error) {
+	v, ok := i.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	// Empty values are left for the schema to require.
+	if v == "" {
+		return
+	}
+
+	if _, err := nrql.Parse(v); err != nil {
+		s, es = syntaxErrorDiagnostics(s, es, fmt.Sprintf("%s is not valid NRQL", k), fmt.Sprintf("%s may not be valid NRQL", k), err)
+	}
+
+	return
+}
+
+// validateNrqlConditionQuery tests if the provided value is a NRQL query that
+// can be evaluated by an alert condition. Conditions query a single value
+// over the window they evaluate, so the clauses choosing the time range or
+// returning more values than one are not allowed. Syntax errors are reported
+// as for validateNrql, and the rules for conditions are still applied to
+// queries with unknown keywords.
+func validateNrqlConditionQuery(i interface{}, k string) (s []string, es []error) {
+	v, ok := i.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	if v == "" {
+		return
+	}
+
+	q, err := nrql.Parse(v)
+	if err != nil {
+		s, es = syntaxErrorDiagnostics(s, es, fmt.Sprintf("%s is not valid NRQL", k), fmt.Sprintf("%s may not be valid NRQL", k), err)
+	}
+
+	if q == nil {
+		return
+	}
+
+	if q.ShowEventTypes {
+		es = append(es, fmt.Errorf("%s cannot be a SHOW EVENT TYPES query in an alert condition", k))
+		return
+	}
+
+	clauses := []string{}
+	if q.Since != nil {
+		clauses = append(clauses, "SINCE")
+	}
+	if q.Until != nil {
+		clauses = append(clauses, "UNTIL")
+	}
+	if q.CompareWith != nil {
+		clauses = append(clauses, "COMPARE WITH")
+	}
+	if q.Timeseries != nil {
+		clauses = append(clauses, "TIMESERIES")
+	}
+
+	if len(clauses) > 0 {
+		es = append(es, fmt.Errorf("%s cannot use %s in an alert condition, which sets the time range itself", k, strings.Join(clauses, ", ")))
+	}
+
+	if len(q.Select) != 1 {
+		es = append(es, fmt.Errorf("%s must select exactly one value in an alert condition, got %d", k, len(q.Select)))
+	}
+
+	return
+}
+
+// validateEntitySearchQuery tests if the provided value is an entity search
+// query with a valid syntax, which is that of a NRQL WHERE clause. Syntax
+// errors are reported as for validateNrql.
+func validateEntitySearchQuery(i interface{}, k string) (s []string, es []error) {
+	v, ok := i.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	if v == "" {
+		return
+	}
+
+	if _, err := nrql.ParseCondition(v); err != nil {
+		s, es = syntaxErrorDiagnostics(s, es, fmt.Sprintf("%s is not a valid entity search query", k), fmt.Sprintf("%s may not be a valid entity search query", k), err)
+	}
+
+	return
+}
+
+// syntaxErrorDiagnostics adds err from parsing a query to the warnings or
+// errors of a SchemaValidateFunc. Unknown keywords are warnings, prefixed
+// with maybe, and any other error is an error, prefixed with invalid.
+func syntaxErrorDiagnostics(s []string, es []error, invalid, maybe string, err error) ([]string, []error) {
+	var syntaxErr *nrql.SyntaxError
+	if errors.As(err, &syntaxErr) && syntaxErr.Unknown {
+		return append(s, fmt.Sprintf("%s: %s", maybe, err)), es
+	}
+
+	return s, append(es, fmt.Errorf("%s: %s", invalid, err))
+}
@@ -8,9 +8,10 @@ import (
 )
 
 type testCase struct {
-	val         interface{}
-	f           schema.SchemaValidateFunc
-	expectedErr *regexp.Regexp
+	val          interface{}
+	f            schema.SchemaValidateFunc
+	expectedErr  *regexp.Regexp
+	expectedWarn *regexp.Regexp
 }
 
 func TestValidationIntInInSlice(t *testing.T) {
@@ -78,6 +79,110 @@ func TestValidationFloat64AtLeast(t *testing.T) {
 	})
 }
 
+func TestValidationNrql(t *testing.T) {
+	runTestCases(t, []testCase{
+		{
+			val: "SELECT average(duration) FROM Transaction FACET appName TIMESERIES auto SINCE 1 day ago",
+			f:   validateNrql,
+		},
+		{
+			val: "",
+			f:   validateNrql,
+		},
+		{
+			val: "SELECT apdex(duration, t: 0.5) FROM Transaction // Checkout\nWHERE appName = 'checkout'",
+			f:   validateNrql,
+		},
+		{
+			val:         "SELECT average(duration) FROM Transaction FACET",
+			f:           validateNrql,
+			expectedErr: regexp.MustCompile(`test_property is not valid NRQL: syntax error at position 48: expected a value, got end of query`),
+		},
+		{
+			val:         "SELECT count(*) FROM Transaction WHERE (a = 1",
+			f:           validateNrql,
+			expectedErr: regexp.MustCompile(`test_property is not valid NRQL: syntax error at position 46: expected \), got end of query`),
+		},
+		{
+			val:         "SELECT count(*) FORM Transaction",
+			f:           validateNrql,
+			expectedErr: regexp.MustCompile(`test_property is not valid NRQL: syntax error at position 17: unknown keyword "FORM", did you mean FROM\?`),
+		},
+		{
+			val:          "SELECT count(*) FROM Transaction PREDICT BY 1 hour",
+			f:            validateNrql,
+			expectedWarn: regexp.MustCompile(`test_property may not be valid NRQL: syntax error at position 34: unknown keyword "PREDICT"`),
+		},
+		{
+			val:         1,
+			f:           validateNrql,
+			expectedErr: regexp.MustCompile(`expected type of [\w]+ to be string`),
+		},
+	})
+}
+
+func TestValidationNrqlConditionQuery(t *testing.T) {
+	runTestCases(t, []testCase{
+		{
+			val: "SELECT percentile(duration, 95) FROM Transaction WHERE appName = 'ExampleAppName' FACET host",
+			f:   validateNrqlConditionQuery,
+		},
+		{
+			val: "",
+			f:   validateNrqlConditionQuery,
+		},
+		{
+			val:         "SELECT count(*) FROM Transaction SINCE 5 minutes ago TIMESERIES",
+			f:           validateNrqlConditionQuery,
+			expectedErr: regexp.MustCompile(`test_property cannot use SINCE, TIMESERIES in an alert condition`),
+		},
+		{
+			val:         "SELECT count(*), average(duration) FROM Transaction",
+			f:           validateNrqlConditionQuery,
+			expectedErr: regexp.MustCompile(`test_property must select exactly one value in an alert condition, got 2`),
+		},
+		{
+			val:         "SELECT count(*) FROM Transaction WHERE",
+			f:           validateNrqlConditionQuery,
+			expectedErr: regexp.MustCompile(`test_property is not valid NRQL: syntax error at position 39`),
+		},
+		{
+			val:          "SELECT count(*) FROM Transaction PREDICT BY 1 hour",
+			f:            validateNrqlConditionQuery,
+			expectedWarn: regexp.MustCompile(`test_property may not be valid NRQL: syntax error at position 34: unknown keyword "PREDICT"`),
+		},
+		{
+			val:          "SELECT count(*) FROM Transaction PREDICT BY 1 hour SINCE 5 minutes ago",
+			f:            validateNrqlConditionQuery,
+			expectedWarn: regexp.MustCompile(`test_property may not be valid NRQL: syntax error at position 34: unknown keyword "PREDICT"`),
+			expectedErr:  regexp.MustCompile(`test_property cannot use SINCE in an alert condition`),
+		},
+	})
+}
+
+func TestValidationEntitySearchQuery(t *testing.T) {
+	runTestCases(t, []testCase{
+		{
+			val: "name like 'Example application' AND domain IN ('APM', 'BROWSER')",
+			f:   validateEntitySearchQuery,
+		},
+		{
+			val: "",
+			f:   validateEntitySearchQuery,
+		},
+		{
+			val:         "name like 'Example application",
+			f:           validateEntitySearchQuery,
+			expectedErr: regexp.MustCompile(`test_property is not a valid entity search query: syntax error at position 11: unterminated string`),
+		},
+		{
+			val:          "name = 'Example application' MATCHES domain",
+			f:            validateEntitySearchQuery,
+			expectedWarn: regexp.MustCompile(`test_property may not be a valid entity search query: syntax error at position 30: unknown keyword "MATCHES"`),
+		},
+	})
+}
+
 func runTestCases(t *testing.T, cases []testCase) {
 	matchErr := func(errs []error, r *regexp.Regexp) bool {
 		// err must match one provided
@@ -90,8 +195,26 @@ func runTestCases(t *testing.T, cases []testCase) {
 		return false
 	}
 
+	matchWarn := func(warns []string, r *regexp.Regexp) bool {
+		for _, warn := range warns {
+			if r.MatchString(warn) {
+				return true
+			}
+		}
+
+		return false
+	}
+
 	for i, tc := range cases {
-		_, errs := tc.f(tc.val, "test_property")
+		warns, errs := tc.f(tc.val, "test_property")
+
+		if tc.expectedWarn != nil && !matchWarn(warns, tc.expectedWarn) {
+			t.Fatalf("expected test case %d to produce warning matching \"%s\", got %v", i, tc.expectedWarn, warns)
+		}
+
+		if tc.expectedWarn == nil && len(warns) > 0 {
+			t.Fatalf("expected test case %d to produce no warning, got %v", i, warns)
+		}
 
 		if len(errs) == 0 && tc.expectedErr == nil {
 			continue